@@ -0,0 +1,166 @@
+package collision
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/TheRaizer/GolangGame/core"
+	"github.com/TheRaizer/GolangGame/display"
+	"github.com/TheRaizer/GolangGame/util/datastructures/aabbtree"
+	"github.com/TheRaizer/GolangGame/util/datastructures/quadtree"
+	"github.com/TheRaizer/GolangGame/util/datastructures/spatialhash"
+	"github.com/TheRaizer/GolangGame/util/datastructures/sweepandprune"
+)
+
+// Compares the broad phase backends on platformer scenes with many static tiles and few moving colliders.
+// Run with: go test ./core/collision -run '^$' -bench BroadPhase
+
+const tileSize float32 = 32
+
+type broadPhase struct {
+	name    string
+	newTree func() quadtree.QuadTree
+	static  bool // whether the level geometry is bulk loaded in to the static layer
+}
+
+func newBenchQuadTree() quadtree.QuadTree {
+	tree := quadtree.NewQuadTree(treeThreshold, treeMaxDepth, quadtree.Rect{X: 0, Y: 0, W: display.WIDTH, H: display.HEIGHT})
+	return &tree
+}
+
+var broadPhases = []broadPhase{
+	{"quadtree", newBenchQuadTree, false},
+	{"quadtree_static", newBenchQuadTree, true},
+	{"spatialhash", func() quadtree.QuadTree {
+		hash := spatialhash.NewSpatialHash(4 * tileSize)
+		return &hash
+	}, false},
+	{"sweepandprune", func() quadtree.QuadTree {
+		sap := sweepandprune.NewSweepAndPrune()
+		return &sap
+	}, false},
+	{"aabbtree", func() quadtree.QuadTree {
+		tree := aabbtree.NewAABBTree()
+		return &tree
+	}, false},
+}
+
+type benchScene struct {
+	name    string
+	columns int32 // width of the level in tiles
+	movers  int
+}
+
+var benchScenes = []benchScene{
+	{"screen", display.WIDTH / 32, 5},
+	{"level", 400, 20},
+	{"large_level", 2000, 50},
+}
+
+// Builds a level with a solid floor, a floating platform every few tiles and a wall every screen,
+// with the moving colliders spread out along the floor.
+func buildScene(scene benchScene, phase broadPhase) (*CollisionSystem, []*Collider) {
+	collisionSys := NewCollisionSystemWithTree(phase.newTree())
+	floorY := display.HEIGHT - tileSize
+
+	var staticSys core.System[*Collider] = &collisionSys
+	if phase.static {
+		staticSys = collisionSys.StaticLayer()
+	}
+
+	addStatic := func(id string, rect quadtree.Rect) {
+		NewCollider(core.WALL_LAYER, id, rect, staticSys, &collisionSys, nil, nil)
+	}
+
+	for column := int32(0); column < scene.columns; column++ {
+		x := float32(column) * tileSize
+		addStatic(fmt.Sprintf("floor_%d", column), quadtree.Rect{X: x, Y: floorY, W: tileSize, H: tileSize})
+
+		if column%8 < 3 {
+			platformY := floorY - tileSize*float32(4+column%3)
+			addStatic(fmt.Sprintf("platform_%d", column), quadtree.Rect{X: x, Y: platformY, W: tileSize, H: tileSize})
+		}
+
+		if column%25 == 0 {
+			for row := int32(1); row <= 4; row++ {
+				addStatic(fmt.Sprintf("wall_%d_%d", column, row), quadtree.Rect{X: x, Y: floorY - float32(row)*tileSize, W: tileSize, H: tileSize})
+			}
+		}
+	}
+
+	movers := make([]*Collider, 0, scene.movers)
+	spacing := float32(scene.columns) * tileSize / float32(scene.movers)
+	for i := 0; i < scene.movers; i++ {
+		rect := quadtree.Rect{X: float32(i)*spacing + tileSize/2, Y: floorY - tileSize, W: tileSize, H: tileSize}
+		movers = append(movers, NewCollider(core.PLAYER_LAYER, fmt.Sprintf("mover_%d", i), rect, &collisionSys, &collisionSys, nil, nil))
+	}
+
+	if phase.static {
+		collisionSys.BuildStaticLayer()
+	}
+
+	return &collisionSys, movers
+}
+
+// Cost of loading a level, the static variants bulk load the level geometry
+func BenchmarkBroadPhaseLoad(b *testing.B) {
+	for _, scene := range benchScenes {
+		for _, phase := range broadPhases {
+			b.Run(scene.name+"/"+phase.name, func(b *testing.B) {
+				b.ReportAllocs()
+
+				for i := 0; i < b.N; i++ {
+					buildScene(scene, phase)
+				}
+			})
+		}
+	}
+}
+
+// Cost of every moving collider querying for collisions, the work done each frame
+func BenchmarkBroadPhaseQuery(b *testing.B) {
+	for _, scene := range benchScenes {
+		for _, phase := range broadPhases {
+			b.Run(scene.name+"/"+phase.name, func(b *testing.B) {
+				collisionSys, movers := buildScene(scene, phase)
+				buf := make([]quadtree.QuadElement, 0)
+				b.ReportAllocs()
+				b.ResetTimer()
+
+				for i := 0; i < b.N; i++ {
+					for _, mover := range movers {
+						buf = collisionSys.DetectCollisionsAppend(mover.Rect, nil, buf[:0])
+					}
+				}
+
+				b.ReportMetric(float64(b.Elapsed().Nanoseconds())/float64(b.N*len(movers)), "ns/query")
+			})
+		}
+	}
+}
+
+// Cost of every moving collider walking a few pixels back and forth, the work done each frame
+func BenchmarkBroadPhaseUpdate(b *testing.B) {
+	for _, scene := range benchScenes {
+		for _, phase := range broadPhases {
+			b.Run(scene.name+"/"+phase.name, func(b *testing.B) {
+				_, movers := buildScene(scene, phase)
+				b.ReportAllocs()
+				b.ResetTimer()
+
+				for i := 0; i < b.N; i++ {
+					var distX float32 = 3
+					if i%2 == 1 {
+						distX = -3
+					}
+
+					for _, mover := range movers {
+						mover.UpdatePos(distX, 0)
+					}
+				}
+
+				b.ReportMetric(float64(b.Elapsed().Nanoseconds())/float64(b.N*len(movers)), "ns/update")
+			})
+		}
+	}
+}
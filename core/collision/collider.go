@@ -10,11 +10,83 @@ import (
 type Collider struct {
 	core.BaseGameObject
 
-	Rect              quadtree.Rect
+	Rect              quadtree.Rect      // the bounding box of the shape when the collider has one
+	shape             Shape              // nil when the collider is the axis-aligned Rect
+	offset            util.Vec2[float32] // where the rect or shape is placed relative to the position of the collider
+	isTrigger         bool               // triggers detect overlaps without blocking movement
+	isOneWay          bool               // one-way platforms only block rigid bodies falling on to them from above
+	isDisabled        bool               // disabled colliders are left out of the collision system until enabled
+	body              core.GameObject    // what moves the collider, such as a rigid body or a moving platform
 	collisionEvents   []func(els []quadtree.QuadElement)
+	enterEvents       []func(collision Collision)
+	stayEvents        []func(collision Collision)
+	exitEvents        []func(collision Collision)
+	triggerEnter      []func(collision Collision)
+	triggerStay       []func(collision Collision)
+	triggerExit       []func(collision Collision)
 	collisionMediator CollisionSystemMediator
 }
 
+// An overlap between a collider and another collider
+type Collision struct {
+	Other       *Collider
+	Owner       core.GameObject  // the game object the other collider belongs to
+	Interaction core.Interaction // how the layers of the two colliders interact
+	Manifold    Manifold         // the contact from the collider to the other collider
+}
+
+// Makes the collider a trigger, whose overlaps are reported through the trigger events rather than the
+// collision events and never block the movement of rigid bodies. Useful for sensors, checkpoints and damage areas.
+func (collider *Collider) SetTrigger(isTrigger bool) {
+	collider.isTrigger = isTrigger
+}
+
+func (collider *Collider) IsTrigger() bool {
+	return collider.isTrigger
+}
+
+// Makes the collider a one-way platform, which rigid bodies can jump through from below and
+// only land on when falling from above its top edge
+func (collider *Collider) SetOneWay(isOneWay bool) {
+	collider.isOneWay = isOneWay
+}
+
+func (collider *Collider) IsOneWay() bool {
+	return collider.isOneWay
+}
+
+// Returns what moves the collider, such as a rigid body or a moving platform,
+// which is nil for colliders that are only moved by their parent
+func (collider *Collider) Body() core.GameObject {
+	return collider.body
+}
+
+// Sets what moves the collider in response to its collisions, so that other bodies colliding with it can respond to it
+func (collider *Collider) SetBody(body core.GameObject) {
+	collider.body = body
+}
+
+// Returns how the two colliders interact according to their layers in the registry.
+// Colliders that would collide only trigger when either of them is a trigger.
+func Interaction(registry *core.LayerRegistry, collider *Collider, other *Collider) core.Interaction {
+	interaction := registry.Interaction(collider.Layer(), other.Layer())
+	if interaction == core.COLLIDE && (collider.isTrigger || other.isTrigger) {
+		return core.TRIGGER
+	}
+
+	return interaction
+}
+
+// Returns the game object that the collider belongs to, which is its parent,
+// or the collider itself when it has no parent
+func (collider *Collider) Owner() core.GameObject {
+	if parent := collider.Parent(); parent != nil {
+		return parent
+	}
+
+	return collider
+}
+
 func NewCollider(
 	layer int,
 	name string,
@@ -30,8 +102,8 @@ func NewCollider(
 			layer,
 			name,
 			util.Vec2[float32]{
-				X: float32(rect.X),
-				Y: float32(rect.Y),
+				X: rect.X,
+				Y: rect.Y,
 			},
 			gameObjectStore,
 		),
@@ -43,17 +115,111 @@ func NewCollider(
 	return &collider
 }
 
+// Creates a collider at pos covering the given shape, which is positioned relative to pos
+func NewShapeCollider(
+	layer int,
+	name string,
+	pos util.Vec2[float32],
+	shape Shape,
+	system core.System[*Collider],
+	collisionMediator CollisionSystemMediator,
+	collisionEvents []func(els []quadtree.QuadElement),
+	gameObjectStore core.GameObjectStore,
+) *Collider {
+	collider := Collider{
+		Rect:              shape.Bounds(pos),
+		shape:             shape,
+		BaseGameObject:    core.NewBaseGameObject(layer, name, pos, gameObjectStore),
+		collisionMediator: collisionMediator,
+		collisionEvents:   collisionEvents,
+	}
+	system.RegisterObject(&collider)
+
+	return &collider
+}
+
+// Returns the shape of the collider, which is nil when the collider is an axis-aligned rect
+func (collider *Collider) Shape() Shape {
+	return collider.shape
+}
+
+// Replaces the shape of the collider, such as with a rotated copy of its shape.
+// Setting a nil shape makes the collider an axis-aligned rect the size of the bounding box of its previous shape.
+func (collider *Collider) SetShape(shape Shape) {
+	collider.shape = shape
+	collider.updateRect(collider.rectAt(collider.Pos))
+}
+
+// Resizes a rect collider, keeping its top left corner in place, such as for a crouching hitbox.
+// Colliders with a shape are resized by setting a different shape.
+func (collider *Collider) SetSize(w float32, h float32) {
+	if collider.shape != nil {
+		panic("only rect colliders can be resized, set a different shape instead")
+	}
+
+	collider.updateRect(quadtree.Rect{X: collider.Rect.X, Y: collider.Rect.Y, W: w, H: h})
+}
+
+// Returns where the rect or shape of the collider is placed relative to the position of the collider
+func (collider *Collider) Offset() util.Vec2[float32] {
+	return collider.offset
+}
+
+// Places the rect or shape of the collider at the offset from the position of the collider, which moves with its parent.
+// Useful for hitboxes that sit in front of their owner, or for keeping the feet of a crouching hitbox on the ground.
+func (collider *Collider) SetOffset(offset util.Vec2[float32]) {
+	collider.offset = offset
+	collider.updateRect(collider.rectAt(collider.Pos))
+}
+
+// Puts a disabled collider back in to the collision system at its current rect
+func (collider *Collider) Enable() {
+	if !collider.isDisabled {
+		return
+	}
+
+	collider.isDisabled = false
+	collider.collisionMediator.EnableCollider(collider)
+}
+
+// Takes the collider out of the collision system without deregistering it, such as for an open door
+// or an attack hitbox between attacks. Colliders overlapping it exit their contact with it on the next loop.
+// The collider can still be moved and resized while disabled.
+func (collider *Collider) Disable() {
+	if collider.isDisabled {
+		return
+	}
+
+	collider.isDisabled = true
+	collider.collisionMediator.DisableCollider(collider)
+}
+
+func (collider *Collider) IsEnabled() bool {
+	return !collider.isDisabled
+}
+
 // Update the position of the collider in the collision system
 func (collider *Collider) UpdatePos(distX float32, distY float32) {
 	collider.BaseGameObject.UpdatePos(distX, distY)
+	collider.updateRect(collider.rectAt(collider.Pos))
+}
+
+// Returns the rect the collider covers when it is at pos
+func (collider *Collider) rectAt(pos util.Vec2[float32]) quadtree.Rect {
+	origin := pos.Add(collider.offset)
+	if collider.shape != nil {
+		return collider.shape.Bounds(origin)
+	}
 
-	newRect := quadtree.Rect{
-		X: int32(collider.Pos.X),
-		Y: int32(collider.Pos.Y),
+	return quadtree.Rect{
+		X: origin.X,
+		Y: origin.Y,
 		W: collider.Rect.W,
 		H: collider.Rect.H,
 	}
+}
 
+func (collider *Collider) updateRect(newRect quadtree.Rect) {
 	collider.collisionMediator.UpdateCollider(collider.ID(), collider.Rect, newRect)
 	collider.Rect = newRect
 }
@@ -62,9 +228,75 @@ func (collider *Collider) AddCollisionEvent(event func(els []quadtree.QuadElemen
 	collider.collisionEvents = append(collider.collisionEvents, event)
 }
 
+// Registers an event called on the first frame the collider overlaps another collider
+func (collider *Collider) AddCollisionEnterEvent(event func(collision Collision)) {
+	collider.enterEvents = append(collider.enterEvents, event)
+}
+
+// Registers an event called on every frame after the first that the collider keeps overlapping another collider
+func (collider *Collider) AddCollisionStayEvent(event func(collision Collision)) {
+	collider.stayEvents = append(collider.stayEvents, event)
+}
+
+// Registers an event called on the first frame the collider no longer overlaps another collider
+func (collider *Collider) AddCollisionExitEvent(event func(collision Collision)) {
+	collider.exitEvents = append(collider.exitEvents, event)
+}
+
+// Registers an event called on the first frame the collider overlaps another collider as a trigger
+func (collider *Collider) AddTriggerEnterEvent(event func(collision Collision)) {
+	collider.triggerEnter = append(collider.triggerEnter, event)
+}
+
+// Registers an event called on every frame after the first that the collider keeps overlapping another collider as a trigger
+func (collider *Collider) AddTriggerStayEvent(event func(collision Collision)) {
+	collider.triggerStay = append(collider.triggerStay, event)
+}
+
+// Registers an event called on the first frame the collider no longer overlaps another collider as a trigger
+func (collider *Collider) AddTriggerExitEvent(event func(collision Collision)) {
+	collider.triggerExit = append(collider.triggerExit, event)
+}
+
 // executes all the collision events with the given collision elements
 func (collider *Collider) OnCollision(els []quadtree.QuadElement) {
 	for _, event := range collider.collisionEvents {
 		event(els)
 	}
 }
+
+func (collider *Collider) OnCollisionEnter(collision Collision) {
+	for _, event := range collider.enterEvents {
+		event(collision)
+	}
+}
+
+func (collider *Collider) OnCollisionStay(collision Collision) {
+	for _, event := range collider.stayEvents {
+		event(collision)
+	}
+}
+
+func (collider *Collider) OnCollisionExit(collision Collision) {
+	for _, event := range collider.exitEvents {
+		event(collision)
+	}
+}
+
+func (collider *Collider) OnTriggerEnter(collision Collision) {
+	for _, event := range collider.triggerEnter {
+		event(collision)
+	}
+}
+
+func (collider *Collider) OnTriggerStay(collision Collision) {
+	for _, event := range collider.triggerStay {
+		event(collision)
+	}
+}
+
+func (collider *Collider) OnTriggerExit(collision Collision) {
+	for _, event := range collider.triggerExit {
+		event(collision)
+	}
+}
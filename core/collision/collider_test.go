@@ -159,6 +159,16 @@ func TestUpdatePosShouldUpdateColliderRect(t *testing.T) {
 				quadtree.Rect{X: 1, Y: 8, W: 12, H: 10},
 			},
 		},
+		{
+			Name: getName,
+			Input: TestInput{
+				quadtree.Rect{X: 4, Y: 1, W: 12, H: 10},
+				util.Vec2[float32]{X: 0.25, Y: -0.5},
+			},
+			Expected: Expected{
+				quadtree.Rect{X: 4.25, Y: 0.5, W: 12, H: 10},
+			},
+		},
 	}
 
 	util.IterateTestCases(cases, t, func(testCase util.TestCase[TestInput, Expected]) {
@@ -261,3 +271,58 @@ func TestOnCollisionShouldCallCollisionEvents(t *testing.T) {
 		require.True(t, called)
 	}
 }
+
+func TestUpdatePosShouldAccumulateSubPixelMovement(t *testing.T) {
+	mockSys := &MockCollisionSystem{}
+	mockSys.On("RegisterObject", mock.Anything)
+	mockSys.On("UpdateCollider", mock.Anything, mock.Anything, mock.Anything)
+
+	collider := NewCollider(0, "id", quadtree.Rect{X: 0, Y: 0, W: 10, H: 10}, mockSys, mockSys, nil, nil)
+
+	// each step is less than a pixel and would be lost if the rect were truncated to whole pixels
+	for i := 0; i < 4; i++ {
+		collider.UpdatePos(0.5, 0.25)
+	}
+
+	require.Equal(t, quadtree.Rect{X: 2, Y: 1, W: 10, H: 10}, collider.Rect)
+}
+
+func TestSetSizeAndOffsetShouldUpdateColliderInSys(t *testing.T) {
+	mockSys := &MockCollisionSystem{}
+	mockSys.On("RegisterObject", mock.Anything)
+	mockSys.On("UpdateCollider", "id", quadtree.Rect{X: 0, Y: 0, W: 10, H: 20}, quadtree.Rect{X: 0, Y: 0, W: 10, H: 10})
+	mockSys.On("UpdateCollider", "id", quadtree.Rect{X: 0, Y: 0, W: 10, H: 10}, quadtree.Rect{X: 0, Y: 10, W: 10, H: 10})
+	mockSys.On("UpdateCollider", "id", quadtree.Rect{X: 0, Y: 10, W: 10, H: 10}, quadtree.Rect{X: 5, Y: 13, W: 10, H: 10})
+
+	collider := NewCollider(0, "id", quadtree.Rect{X: 0, Y: 0, W: 10, H: 20}, mockSys, mockSys, nil, nil)
+
+	// crouch by halving the height while keeping the bottom of the collider in place
+	collider.SetSize(10, 10)
+	collider.SetOffset(util.Vec2[float32]{X: 0, Y: 10})
+
+	// the offset is kept as the collider moves
+	collider.UpdatePos(5, 3)
+
+	mockSys.AssertExpectations(t)
+	require.Equal(t, quadtree.Rect{X: 5, Y: 13, W: 10, H: 10}, collider.Rect)
+	require.Equal(t, util.Vec2[float32]{X: 5, Y: 3}, collider.Pos)
+}
+
+func TestSetOffsetShouldMoveShape(t *testing.T) {
+	collider := newTestCollider(placedShape{vec{X: 0, Y: 0}, Circle{vec{}, 5}})
+	collider.collisionMediator.(*MockCollisionSystem).On("UpdateCollider", mock.Anything, mock.Anything, mock.Anything)
+
+	collider.SetOffset(util.Vec2[float32]{X: 20, Y: 0})
+
+	require.Equal(t, quadtree.Rect{X: 15, Y: -5, W: 10, H: 10}, collider.Rect)
+	require.True(t, collider.Overlaps(newTestCollider(placedShape{vec{X: 16, Y: 0}, nil})))
+	require.False(t, collider.Overlaps(newTestCollider(placedShape{vec{X: -4, Y: 0}, nil})))
+}
+
+func TestSetSizeShouldPanicWithShape(t *testing.T) {
+	collider := newTestCollider(placedShape{vec{X: 0, Y: 0}, Circle{vec{}, 5}})
+
+	require.PanicsWithValue(t, "only rect colliders can be resized, set a different shape instead", func() {
+		collider.SetSize(5, 5)
+	})
+}
@@ -1,42 +1,355 @@
 package collision
 
-import "github.com/TheRaizer/GolangGame/util/datastructures/quadtree"
+import (
+	"github.com/TheRaizer/GolangGame/core"
+	"github.com/TheRaizer/GolangGame/util"
+	"github.com/TheRaizer/GolangGame/util/datastructures/quadtree"
+)
 
 type CollisionSystemMediator interface {
 	UpdateCollider(id string, oldRect quadtree.Rect, newRect quadtree.Rect)
 	DetectCollisions(rect quadtree.Rect) []quadtree.QuadElement
+	DetectContacts(collider *Collider, rect quadtree.Rect, buf []Manifold) []Manifold
+	EnableCollider(collider *Collider)
+	DisableCollider(collider *Collider)
+	OverlapBox(rect quadtree.Rect, filter QueryFilter, buf []*Collider) []*Collider
+	OverlapCircle(center util.Vec2[float32], radius float32, filter QueryFilter, buf []*Collider) []*Collider
+	Raycast(origin util.Vec2[float32], direction util.Vec2[float32], maxDistance float32, filter QueryFilter) (Hit, bool)
+	BoxCast(rect quadtree.Rect, direction util.Vec2[float32], maxDistance float32, filter QueryFilter) (Hit, bool)
+	Layers() *core.LayerRegistry
 }
 
+// the threshold and max depth of the quadtrees created by the collision system
+const (
+	treeThreshold uint8 = 7
+	treeMaxDepth  uint8 = 5
+)
+
 type CollisionSystem struct {
 	tree      quadtree.QuadTree
 	colliders map[string]*Collider
+	registry  *core.LayerRegistry // decides which colliders interact
+
+	static            *quadtree.BaseQuadTree // bulk loaded from the static colliders, nil until the static layer is built
+	staticColliders   map[string]*Collider
+	staticBuilt       map[string]bool        // the ids of the static colliders bulk loaded by the last build
+	disabledColliders map[string]*Collider   // registered colliders that are left out of the tree until enabled
+	queryBuf          []quadtree.QuadElement // reused by OnLoop so that querying every frame does not allocate
+	contactQueryBuf   []quadtree.QuadElement // reused by DetectContacts, separate so it can be called from collision callbacks
+	sceneQueryBuf     []quadtree.QuadElement // reused by the scene queries, for the same reason
+
+	workers        int // number of goroutines OnLoop queries with, queries run sequentially when at most 1
+	loopColliders  []*Collider
+	loopHitboxes   []quadtree.Rect
+	loopCollisions [][]quadtree.QuadElement
+
+	// the colliders each collider overlapped on the previous loop and is overlapping on the current loop,
+	// swapped at the end of each loop so that tracking contacts does not allocate once the maps have grown
+	contacts     map[contactKey]*Collider
+	nextContacts map[contactKey]*Collider
+}
+
+// a collider and another collider that it overlaps
+type contactKey struct {
+	id      string
+	otherId string
 }
 
 func NewCollisionSystem(globalRect quadtree.Rect) CollisionSystem {
-	tree := quadtree.NewQuadTree(7, 5, globalRect)
+	tree := quadtree.NewQuadTree(treeThreshold, treeMaxDepth, globalRect)
+	return NewCollisionSystemWithTree(&tree)
+}
+
+// Creates a collision system that uses the given broad phase, which can be any spatial
+// structure that implements the QuadTree interface.
+func NewCollisionSystemWithTree(tree quadtree.QuadTree) CollisionSystem {
+	registry := core.NewLayerRegistry()
+
 	return CollisionSystem{
-		tree:      &tree,
-		colliders: make(map[string]*Collider),
+		tree:              tree,
+		registry:          &registry,
+		colliders:         make(map[string]*Collider),
+		staticColliders:   make(map[string]*Collider),
+		disabledColliders: make(map[string]*Collider),
+		contacts:          make(map[contactKey]*Collider),
+		nextContacts:      make(map[contactKey]*Collider),
 	}
 }
 
-// Checks for collisions between registered colliders and calls their OnCollision callback
+// Returns the layer registry whose collision matrix decides which colliders interact
+func (collisionSys *CollisionSystem) Layers() *core.LayerRegistry {
+	return collisionSys.registry
+}
+
+// Replaces the layer registry, such as with one that has had game specific layers registered
+func (collisionSys *CollisionSystem) SetLayers(registry *core.LayerRegistry) {
+	collisionSys.registry = registry
+}
+
+// Returns the system to create the colliders of static level geometry with
+func (collisionSys *CollisionSystem) StaticLayer() StaticLayer {
+	return StaticLayer{collisionSys}
+}
+
+// Bulk loads every collider registered with the static layer in to a balanced quadtree.
+// Call once the level has been loaded, static colliders are not collided with until it is built.
+func (collisionSys *CollisionSystem) BuildStaticLayer() {
+	if len(collisionSys.staticColliders) == 0 {
+		collisionSys.static = nil
+		collisionSys.staticBuilt = nil
+		return
+	}
+
+	els := make([]quadtree.QuadElement, 0, len(collisionSys.staticColliders))
+	built := make(map[string]bool, len(collisionSys.staticColliders))
+	bounds := quadtree.Rect{}
+	for id, collider := range collisionSys.staticColliders {
+		if len(els) == 0 {
+			bounds = collider.Rect
+		}
+
+		els = append(els, quadtree.QuadElement{Rect: collider.Rect, Id: id})
+		built[id] = true
+		bounds = bounds.Union(collider.Rect)
+	}
+
+	static := quadtree.NewQuadTreeFromElements(treeThreshold, treeMaxDepth, bounds, els)
+	collisionSys.static = &static
+	collisionSys.staticBuilt = built
+}
+
+// the static and dynamic trees as one
+func (collisionSys *CollisionSystem) layers() layeredTree {
+	return layeredTree{collisionSys.tree, collisionSys.static}
+}
+
+// Makes OnLoop query for the collisions of the colliders on the given number of goroutines.
+// The tree is wrapped in a SyncQuadTree so that colliders can be safely updated from other
+// goroutines while the queries run. Registering colliders must still happen on a single goroutine.
+func (collisionSys *CollisionSystem) SetWorkers(workers int) {
+	if _, ok := collisionSys.tree.(*quadtree.SyncQuadTree); !ok && workers > 1 {
+		collisionSys.tree = quadtree.NewSyncQuadTree(collisionSys.tree)
+	}
+
+	collisionSys.workers = workers
+}
+
+// Checks for collisions between registered colliders and calls their OnCollision callback,
+// along with their enter, stay and exit callbacks for each collider whose overlap began, continued or ended.
+// Overlaps involving a trigger, or between layers that only trigger, call the trigger callbacks instead.
+// Colliders of the static layer are collided with, but do not have their callbacks called.
+// Colliders are never told about themselves, nor about colliders whose layers they ignore in the collision matrix.
+// The elements given to the callbacks are only valid until the callback returns, since their
+// slice is reused for the next collider.
 func (collisionSys *CollisionSystem) OnLoop() {
+	if collisionSys.workers > 1 {
+		collisionSys.onLoopParallel()
+	} else {
+		for _, collider := range collisionSys.colliders {
+			collisionSys.queryBuf = collisionSys.layers().QueryAppend(collider.Rect, nil, collisionSys.queryBuf[:0])
+			collisionSys.queryBuf = collisionSys.dispatch(collider, collisionSys.queryBuf)
+		}
+	}
+
+	collisionSys.dispatchExits()
+}
+
+// Queries the collisions of every collider in parallel, then calls the OnCollision callbacks
+// on the calling goroutine so that gameplay code does not need to be thread-safe
+func (collisionSys *CollisionSystem) onLoopParallel() {
+	collisionSys.loopColliders = collisionSys.loopColliders[:0]
+	collisionSys.loopHitboxes = collisionSys.loopHitboxes[:0]
+
 	for _, collider := range collisionSys.colliders {
-		els := collisionSys.tree.Query(collider.Rect)
-		collider.OnCollision(els)
+		collisionSys.loopColliders = append(collisionSys.loopColliders, collider)
+		collisionSys.loopHitboxes = append(collisionSys.loopHitboxes, collider.Rect)
+	}
+
+	collisionSys.loopCollisions = quadtree.ParallelQuery(
+		collisionSys.layers(),
+		collisionSys.loopHitboxes,
+		nil,
+		collisionSys.loopCollisions,
+		collisionSys.workers,
+	)
+
+	for i, collider := range collisionSys.loopColliders {
+		collisionSys.loopCollisions[i] = collisionSys.dispatch(collider, collisionSys.loopCollisions[i])
 	}
 }
 
 func (collisionSys *CollisionSystem) DetectCollisions(rect quadtree.Rect) []quadtree.QuadElement {
-	els := collisionSys.tree.Query(rect)
+	els := collisionSys.layers().Query(rect)
 	return els
 }
 
+// Appends the elements colliding with the given rect that pass the filter to buf
+func (collisionSys *CollisionSystem) DetectCollisionsAppend(
+	rect quadtree.Rect,
+	filter quadtree.Filter,
+	buf []quadtree.QuadElement,
+) []quadtree.QuadElement {
+	return collisionSys.layers().QueryAppend(rect, filter, buf)
+}
+
+// Appends the contacts between the collider and the colliders within rect to buf, such as the rect the collider
+// is about to move to. Each manifold is computed with the collider at its current position, so colliders that it
+// has not reached yet have a negative depth of the gap between them. The collider itself and colliders on layers
+// it ignores are left out.
+func (collisionSys *CollisionSystem) DetectContacts(collider *Collider, rect quadtree.Rect, buf []Manifold) []Manifold {
+	collisionSys.contactQueryBuf = collisionSys.layers().QueryAppend(rect, nil, collisionSys.contactQueryBuf[:0])
+
+	for _, el := range collisionSys.contactQueryBuf {
+		other, ok := collisionSys.collider(el.Id)
+		if !ok || other == collider || collisionSys.registry.Interaction(collider.Layer(), other.Layer()) == core.IGNORE {
+			continue
+		}
+
+		buf = append(buf, collider.ContactWith(other))
+	}
+
+	return buf
+}
+
+// Returns a filter that only includes the registered colliders whose layer is in the given mask.
+// Create the filter once and reuse it, since creating it allocates.
+func (collisionSys *CollisionSystem) LayerFilter(mask uint32) quadtree.Filter {
+	return func(el quadtree.QuadElement) bool {
+		collider, ok := collisionSys.collider(el.Id)
+		return ok && mask&(1<<collider.Layer()) != 0
+	}
+}
+
+// Returns a mask with the bit of each given layer set
+func LayerMask(layers ...int) uint32 {
+	var mask uint32
+	for _, layer := range layers {
+		mask |= 1 << layer
+	}
+
+	return mask
+}
+
+// Removes the collider itself, the elements whose layer it ignores and the elements whose shape it does not overlap
+// from the elements whose bounding box it collided with in place,
+// then calls the callbacks of the collider with the remaining elements and records its contacts.
+// Filtering after querying rather than with a filter avoids allocating a closure for each collider.
+func (collisionSys *CollisionSystem) dispatch(collider *Collider, els []quadtree.QuadElement) []quadtree.QuadElement {
+	kept := els[:0]
+	for _, el := range els {
+		if el.Id == collider.ID() {
+			continue
+		}
+
+		other, ok := collisionSys.collider(el.Id)
+		if ok && (collisionSys.registry.Interaction(collider.Layer(), other.Layer()) == core.IGNORE || !collider.Overlaps(other)) {
+			continue
+		}
+
+		kept = append(kept, el)
+	}
+
+	collider.OnCollision(kept)
+
+	for _, el := range kept {
+		other, ok := collisionSys.collider(el.Id)
+		if !ok {
+			continue
+		}
+
+		key := contactKey{collider.ID(), other.ID()}
+		collision := Collision{other, other.Owner(), Interaction(collisionSys.registry, collider, other), collider.ContactWith(other)}
+		_, stayed := collisionSys.contacts[key]
+
+		switch {
+		case collision.Interaction == core.TRIGGER && stayed:
+			collider.OnTriggerStay(collision)
+		case collision.Interaction == core.TRIGGER:
+			collider.OnTriggerEnter(collision)
+		case stayed:
+			collider.OnCollisionStay(collision)
+		default:
+			collider.OnCollisionEnter(collision)
+		}
+
+		collisionSys.nextContacts[key] = other
+	}
+
+	return kept
+}
+
+// Calls the exit callbacks for the contacts of the previous loop that did not continue in to this loop,
+// then makes the contacts of this loop the previous contacts for the next loop
+func (collisionSys *CollisionSystem) dispatchExits() {
+	for key, other := range collisionSys.contacts {
+		if _, ok := collisionSys.nextContacts[key]; ok {
+			continue
+		}
+
+		// a deregistered collider is no longer told about its contacts
+		collider, ok := collisionSys.colliders[key.id]
+		if !ok {
+			continue
+		}
+
+		collision := Collision{other, other.Owner(), Interaction(collisionSys.registry, collider, other), collider.ContactWith(other)}
+		if collision.Interaction == core.TRIGGER {
+			collider.OnTriggerExit(collision)
+		} else {
+			collider.OnCollisionExit(collision)
+		}
+	}
+
+	clear(collisionSys.contacts)
+	collisionSys.contacts, collisionSys.nextContacts = collisionSys.nextContacts, collisionSys.contacts
+}
+
+// Returns the registered collider with the given id from either layer
+func (collisionSys *CollisionSystem) collider(id string) (*Collider, bool) {
+	if collider, ok := collisionSys.colliders[id]; ok {
+		return collider, true
+	}
+
+	collider, ok := collisionSys.staticColliders[id]
+	return collider, ok
+}
+
 // call when updating a collider position or size
 func (collisionSys *CollisionSystem) UpdateCollider(id string, oldRect quadtree.Rect, newRect quadtree.Rect) {
-	collisionSys.tree.Remove(quadtree.QuadElement{Rect: oldRect, Id: id})
-	collisionSys.tree.Insert(quadtree.QuadElement{Rect: newRect, Id: id})
+	if _, ok := collisionSys.staticColliders[id]; ok {
+		panic("static colliders cannot be moved: " + id)
+	}
+
+	// disabled colliders are inserted at their latest rect once enabled
+	if _, ok := collisionSys.disabledColliders[id]; ok {
+		return
+	}
+
+	collisionSys.tree.Move(id, oldRect, newRect)
+}
+
+// Removes a registered collider from the tree and the colliders collided with, until it is enabled again
+func (collisionSys *CollisionSystem) DisableCollider(collider *Collider) {
+	if _, ok := collisionSys.staticColliders[collider.ID()]; ok {
+		panic("static colliders cannot be disabled: " + collider.ID())
+	}
+
+	if _, ok := collisionSys.colliders[collider.ID()]; !ok {
+		return
+	}
+
+	collisionSys.DeregisterObject(collider)
+	collisionSys.disabledColliders[collider.ID()] = collider
+}
+
+// Puts a disabled collider back in to the tree at its current rect
+func (collisionSys *CollisionSystem) EnableCollider(collider *Collider) {
+	if _, ok := collisionSys.disabledColliders[collider.ID()]; !ok {
+		return
+	}
+
+	delete(collisionSys.disabledColliders, collider.ID())
+	collisionSys.RegisterObject(collider)
 }
 
 func (collisionSys *CollisionSystem) RegisterObject(collider *Collider) {
@@ -45,6 +358,12 @@ func (collisionSys *CollisionSystem) RegisterObject(collider *Collider) {
 }
 
 func (collisionSys *CollisionSystem) DeregisterObject(collider *Collider) {
+	if _, ok := collisionSys.disabledColliders[collider.ID()]; ok {
+		// a disabled collider is already out of the tree
+		delete(collisionSys.disabledColliders, collider.ID())
+		return
+	}
+
 	delete(collisionSys.colliders, collider.ID())
 	collisionSys.tree.Remove(quadtree.QuadElement{Rect: collider.Rect, Id: collider.ID()})
 }
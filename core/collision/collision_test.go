@@ -1,6 +1,7 @@
 package collision
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/TheRaizer/GolangGame/core"
@@ -34,11 +35,20 @@ func (tree *QuadTreeMock) Remove(el quadtree.QuadElement) {
 	tree.Called(el)
 }
 
+func (tree *QuadTreeMock) Move(id string, oldRect quadtree.Rect, newRect quadtree.Rect) {
+	tree.Called(id, oldRect, newRect)
+}
+
 func (tree *QuadTreeMock) Query(hitbox quadtree.Rect) []quadtree.QuadElement {
 	tree.Called(hitbox)
 	return mockQueryResult
 }
 
+func (tree *QuadTreeMock) QueryAppend(hitbox quadtree.Rect, filter quadtree.Filter, buf []quadtree.QuadElement) []quadtree.QuadElement {
+	tree.Called(hitbox)
+	return append(buf, mockQueryResult...)
+}
+
 func TestRegisterObjectShouldStoreObject(t *testing.T) {
 	collisionSys := NewCollisionSystem(quadtree.Rect{X: 0, Y: 0, W: 50, H: 50})
 
@@ -61,6 +71,19 @@ func TestRegisterObjectShouldStoreObject(t *testing.T) {
 	}
 }
 
+func TestNewCollisionSystemWithTreeShouldUseGivenTree(t *testing.T) {
+	mockTree := &QuadTreeMock{}
+	collisionSys := NewCollisionSystemWithTree(mockTree)
+	rect := quadtree.Rect{X: 1, Y: 2, W: 3, H: 4}
+
+	mockTree.On("Query", rect)
+	els := collisionSys.DetectCollisions(rect)
+
+	mockTree.AssertExpectations(t)
+	require.Equal(t, mockQueryResult, els)
+	require.NotNil(t, collisionSys.colliders)
+}
+
 func TestRegisterObjectShouldInsertIntoQuadTree(t *testing.T) {
 	mockTree := &QuadTreeMock{}
 	collisionSys := &CollisionSystem{
@@ -139,7 +162,7 @@ func TestDeregisterObjectShouldRemoveObjFromMap(t *testing.T) {
 	require.Nil(t, collisionSys.colliders[expectedId])
 }
 
-func TestUpdateColliderShouldReinsertColliderIntoQuadTree(t *testing.T) {
+func TestUpdateColliderShouldMoveColliderInQuadTree(t *testing.T) {
 	mockTree := &QuadTreeMock{}
 	expectedId := "id"
 	collisionSys := &CollisionSystem{
@@ -149,8 +172,7 @@ func TestUpdateColliderShouldReinsertColliderIntoQuadTree(t *testing.T) {
 	oldRect := quadtree.Rect{X: 0, Y: 0, W: 32, H: 32}
 	newRect := quadtree.Rect{X: 10, Y: 10, W: 32, H: 32}
 
-	mockTree.On("Remove", quadtree.QuadElement{Id: expectedId, Rect: oldRect})
-	mockTree.On("Insert", quadtree.QuadElement{Id: expectedId, Rect: newRect})
+	mockTree.On("Move", expectedId, oldRect, newRect)
 
 	collisionSys.UpdateCollider(expectedId, oldRect, newRect)
 	mockTree.AssertExpectations(t)
@@ -208,9 +230,414 @@ func TestOnLoop(t *testing.T) {
 	}
 
 	// should query on each collider
-	mockTree.On("Query", collider1.Rect)
-	mockTree.On("Query", collider2.Rect)
+	mockTree.On("QueryAppend", collider1.Rect)
+	mockTree.On("QueryAppend", collider2.Rect)
 
 	collisionSys.OnLoop()
 	mockTree.AssertExpectations(t)
 }
+
+func TestOnLoopShouldNotAllocate(t *testing.T) {
+	collisionSys := NewCollisionSystem(quadtree.Rect{X: 0, Y: 0, W: 800, H: 600})
+	for i := 0; i < 50; i++ {
+		NewCollider(
+			0,
+			fmt.Sprintf("id%d", i),
+			quadtree.Rect{X: float32(i) * 15, Y: float32(i%10) * 50, W: 32, H: 32},
+			&collisionSys,
+			&collisionSys,
+			[]func(els []quadtree.QuadElement){},
+			nil,
+		)
+	}
+
+	// the first loop grows the reused buffer
+	collisionSys.OnLoop()
+
+	allocs := testing.AllocsPerRun(10, collisionSys.OnLoop)
+	require.Zero(t, allocs)
+}
+
+func TestLayerFilterShouldOnlyIncludeMaskedLayers(t *testing.T) {
+	type TestInput struct {
+		mask uint32
+	}
+
+	const NAME string = "should only include colliders on layers in mask %b"
+	getName := func(input TestInput) string {
+		return fmt.Sprintf(NAME, input.mask)
+	}
+
+	cases := []util.TestCase[TestInput, []string]{
+		{Name: getName, Input: TestInput{LayerMask(core.WALL_LAYER)}, Expected: []string{"wall"}},
+		{Name: getName, Input: TestInput{LayerMask(core.PLAYER_LAYER)}, Expected: []string{"player"}},
+		{Name: getName, Input: TestInput{LayerMask(core.WALL_LAYER, core.PLAYER_LAYER)}, Expected: []string{"wall", "player"}},
+		{Name: getName, Input: TestInput{0}, Expected: []string{}},
+	}
+
+	util.IterateTestCases(cases, t, func(testCase util.TestCase[TestInput, []string]) {
+		collisionSys := NewCollisionSystem(quadtree.Rect{X: 0, Y: 0, W: 100, H: 100})
+		rect := quadtree.Rect{X: 10, Y: 10, W: 10, H: 10}
+		NewCollider(core.WALL_LAYER, "wall", rect, &collisionSys, &collisionSys, nil, nil)
+		NewCollider(core.PLAYER_LAYER, "player", rect, &collisionSys, &collisionSys, nil, nil)
+
+		els := collisionSys.DetectCollisionsAppend(rect, collisionSys.LayerFilter(testCase.Input.mask), nil)
+
+		ids := make([]string, 0)
+		for _, el := range els {
+			ids = append(ids, el.Id)
+		}
+		require.ElementsMatch(t, testCase.Expected, ids)
+	})
+}
+
+func TestOnLoopWithWorkersShouldMatchSequentialLoop(t *testing.T) {
+	const NAME string = "should report the same collisions when using %d workers"
+	getName := func(input int) string {
+		return fmt.Sprintf(NAME, input)
+	}
+
+	cases := []util.TestCase[int, struct{}]{
+		{Name: getName, Input: 2},
+		{Name: getName, Input: 4},
+		{Name: getName, Input: 64},
+	}
+
+	util.IterateTestCases(cases, t, func(testCase util.TestCase[int, struct{}]) {
+		collisionSys := NewCollisionSystem(quadtree.Rect{X: 0, Y: 0, W: 800, H: 600})
+		collided := make(map[string][]quadtree.QuadElement)
+		colliders := make([]*Collider, 0)
+
+		for i := 0; i < 30; i++ {
+			id := fmt.Sprintf("id%d", i)
+			colliders = append(colliders, NewCollider(
+				i%2,
+				id,
+				quadtree.Rect{X: float32(i) * 20, Y: float32(i%3) * 20, W: 32, H: 32},
+				&collisionSys,
+				&collisionSys,
+				[]func(els []quadtree.QuadElement){
+					func(els []quadtree.QuadElement) {
+						// the callbacks run on the calling goroutine so this map write is safe
+						collided[id] = append([]quadtree.QuadElement{}, els...)
+					},
+				},
+				nil,
+			))
+		}
+
+		collisionSys.OnLoop()
+		sequential := collided
+		collided = make(map[string][]quadtree.QuadElement)
+
+		collisionSys.SetWorkers(testCase.Input)
+		collisionSys.OnLoop()
+
+		require.Len(t, collided, len(colliders))
+		for _, collider := range colliders {
+			require.ElementsMatch(t, sequential[collider.ID()], collided[collider.ID()])
+		}
+	})
+}
+
+func TestSetWorkersShouldWrapTreeOnce(t *testing.T) {
+	collisionSys := NewCollisionSystem(quadtree.Rect{X: 0, Y: 0, W: 800, H: 600})
+
+	collisionSys.SetWorkers(1)
+	_, ok := collisionSys.tree.(*quadtree.SyncQuadTree)
+	require.False(t, ok)
+
+	collisionSys.SetWorkers(4)
+	syncTree, ok := collisionSys.tree.(*quadtree.SyncQuadTree)
+	require.True(t, ok)
+
+	collisionSys.SetWorkers(8)
+	require.Same(t, syncTree, collisionSys.tree)
+}
+
+func TestOnLoopShouldNotReportIgnoredLayers(t *testing.T) {
+	type TestInput struct {
+		layer       int
+		interaction core.Interaction
+	}
+
+	const NAME string = "should report wall and %v enemy to layer %d"
+	getName := func(input TestInput) string {
+		return fmt.Sprintf(NAME, input.interaction, input.layer)
+	}
+
+	cases := []util.TestCase[TestInput, []string]{
+		{Name: getName, Input: TestInput{core.PLAYER_LAYER, core.COLLIDE}, Expected: []string{"wall", "enemy"}},
+		{Name: getName, Input: TestInput{core.PLAYER_LAYER, core.TRIGGER}, Expected: []string{"wall", "enemy"}},
+		{Name: getName, Input: TestInput{core.PLAYER_LAYER, core.IGNORE}, Expected: []string{"wall"}},
+		// pickups ignore everything but the player by default
+		{Name: getName, Input: TestInput{core.PICKUP_LAYER, core.COLLIDE}, Expected: []string{"enemy"}},
+	}
+
+	util.IterateTestCases(cases, t, func(testCase util.TestCase[TestInput, []string]) {
+		collisionSys := NewCollisionSystem(quadtree.Rect{X: 0, Y: 0, W: 100, H: 100})
+		collisionSys.Layers().SetInteraction(testCase.Input.layer, core.ENEMY_LAYER, testCase.Input.interaction)
+
+		rect := quadtree.Rect{X: 10, Y: 10, W: 10, H: 10}
+		NewCollider(core.WALL_LAYER, "wall", rect, &collisionSys, &collisionSys, nil, nil)
+		NewCollider(core.ENEMY_LAYER, "enemy", rect, &collisionSys, &collisionSys, nil, nil)
+
+		var collided []string
+		collider := NewCollider(testCase.Input.layer, "collider", rect, &collisionSys, &collisionSys, nil, nil)
+		collider.AddCollisionEvent(func(els []quadtree.QuadElement) {
+			collided = ids(els)
+		})
+
+		collisionSys.OnLoop()
+		require.ElementsMatch(t, testCase.Expected, collided)
+	})
+}
+
+// records the enter, stay and exit events of a collider as "<event>:<other id>"
+func recordContactEvents(collider *Collider, events *[]string) {
+	collider.AddCollisionEnterEvent(func(collision Collision) {
+		*events = append(*events, "enter:"+collision.Other.ID())
+	})
+	collider.AddCollisionStayEvent(func(collision Collision) {
+		*events = append(*events, "stay:"+collision.Other.ID())
+	})
+	collider.AddCollisionExitEvent(func(collision Collision) {
+		*events = append(*events, "exit:"+collision.Other.ID())
+	})
+}
+
+func TestOnLoopShouldCallEnterStayAndExitEvents(t *testing.T) {
+	collisionSys := NewCollisionSystem(quadtree.Rect{X: 0, Y: 0, W: 200, H: 200})
+	NewCollider(core.WALL_LAYER, "wall", quadtree.Rect{X: 50, Y: 0, W: 10, H: 10}, &collisionSys, &collisionSys, nil, nil)
+	player := NewCollider(core.PLAYER_LAYER, "player", quadtree.Rect{X: 30, Y: 0, W: 10, H: 10}, &collisionSys, &collisionSys, nil, nil)
+
+	events := make([]string, 0)
+	recordContactEvents(player, &events)
+
+	steps := []struct {
+		distX    float32
+		expected []string
+	}{
+		{0, []string{}},
+		{15, []string{"enter:wall"}},
+		{5, []string{"stay:wall"}},
+		{0, []string{"stay:wall"}},
+		{30, []string{"exit:wall"}},
+		{0, []string{}},
+		{-25, []string{"enter:wall"}},
+	}
+
+	for i, step := range steps {
+		events = events[:0]
+		player.UpdatePos(step.distX, 0)
+		collisionSys.OnLoop()
+
+		require.Equal(t, step.expected, events, "step %d", i)
+	}
+}
+
+func TestOnLoopShouldNotCollideWithSelf(t *testing.T) {
+	collisionSys := NewCollisionSystem(quadtree.Rect{X: 0, Y: 0, W: 200, H: 200})
+	collider := NewCollider(core.PLAYER_LAYER, "player", quadtree.Rect{X: 30, Y: 0, W: 10, H: 10}, &collisionSys, &collisionSys, nil, nil)
+
+	events := make([]string, 0)
+	recordContactEvents(collider, &events)
+	collider.AddCollisionEvent(func(els []quadtree.QuadElement) {
+		require.Empty(t, els)
+	})
+
+	collisionSys.OnLoop()
+	collisionSys.OnLoop()
+	require.Empty(t, events)
+}
+
+func TestCollisionShouldCarryOtherColliderAndOwner(t *testing.T) {
+	collisionSys := NewCollisionSystem(quadtree.Rect{X: 0, Y: 0, W: 200, H: 200})
+	rect := quadtree.Rect{X: 0, Y: 0, W: 10, H: 10}
+
+	wall := NewCollider(core.WALL_LAYER, "wall", rect, &collisionSys, &collisionSys, nil, nil)
+	enemy := core.NewBaseGameObject(core.ENEMY_LAYER, "enemy", util.Vec2[float32]{}, nil)
+	enemyCollider := NewCollider(core.ENEMY_LAYER, "enemy_collider", rect, &collisionSys, &collisionSys, nil, nil)
+	enemyCollider.SetParent(&enemy)
+
+	player := NewCollider(core.PLAYER_LAYER, "player", rect, &collisionSys, &collisionSys, nil, nil)
+	collisions := make(map[string]Collision)
+	player.AddCollisionEnterEvent(func(collision Collision) {
+		collisions[collision.Other.ID()] = collision
+	})
+
+	collisionSys.OnLoop()
+
+	require.Equal(t, map[string]Collision{
+		// colliders without a parent are their own owner
+		"wall":           {wall, wall, core.COLLIDE, player.ContactWith(wall)},
+		"enemy_collider": {enemyCollider, &enemy, core.COLLIDE, player.ContactWith(enemyCollider)},
+	}, collisions)
+}
+
+func TestDeregisteredColliderShouldExitContacts(t *testing.T) {
+	collisionSys := NewCollisionSystem(quadtree.Rect{X: 0, Y: 0, W: 200, H: 200})
+	rect := quadtree.Rect{X: 0, Y: 0, W: 10, H: 10}
+	wall := NewCollider(core.WALL_LAYER, "wall", rect, &collisionSys, &collisionSys, nil, nil)
+	player := NewCollider(core.PLAYER_LAYER, "player", rect, &collisionSys, &collisionSys, nil, nil)
+
+	playerEvents := make([]string, 0)
+	wallEvents := make([]string, 0)
+	recordContactEvents(player, &playerEvents)
+	recordContactEvents(wall, &wallEvents)
+
+	collisionSys.OnLoop()
+	collisionSys.DeregisterObject(wall)
+	collisionSys.OnLoop()
+
+	require.Equal(t, []string{"enter:wall", "exit:wall"}, playerEvents)
+	// the deregistered wall is not told that it left the player
+	require.Equal(t, []string{"enter:player"}, wallEvents)
+}
+
+func TestOnLoopWithContactsShouldNotAllocate(t *testing.T) {
+	collisionSys := NewCollisionSystem(quadtree.Rect{X: 0, Y: 0, W: 800, H: 600})
+	for i := 0; i < 50; i++ {
+		collider := NewCollider(
+			i%2,
+			fmt.Sprintf("id%d", i),
+			quadtree.Rect{X: float32(i) * 15, Y: float32(i%10) * 50, W: 32, H: 32},
+			&collisionSys,
+			&collisionSys,
+			nil,
+			nil,
+		)
+		collider.AddCollisionStayEvent(func(collision Collision) {})
+	}
+
+	// the first loops grow the reused buffer and both contact maps
+	collisionSys.OnLoop()
+	collisionSys.OnLoop()
+
+	allocs := testing.AllocsPerRun(10, collisionSys.OnLoop)
+	require.Zero(t, allocs)
+}
+
+func TestOnLoopShouldCallTriggerEvents(t *testing.T) {
+	type TestInput struct {
+		wallIsTrigger   bool
+		playerIsTrigger bool
+		interaction     core.Interaction
+	}
+
+	type Expected struct {
+		collisionEvents []string
+		triggerEvents   []string
+	}
+
+	const NAME string = "should report %+v through the right events"
+	getName := func(input TestInput) string {
+		return fmt.Sprintf(NAME, input)
+	}
+
+	cases := []util.TestCase[TestInput, Expected]{
+		{
+			Name:     getName,
+			Input:    TestInput{false, false, core.COLLIDE},
+			Expected: Expected{[]string{"enter:wall", "stay:wall", "exit:wall"}, []string{}},
+		},
+		{
+			Name:     getName,
+			Input:    TestInput{true, false, core.COLLIDE},
+			Expected: Expected{[]string{}, []string{"enter:wall", "stay:wall", "exit:wall"}},
+		},
+		{
+			Name:     getName,
+			Input:    TestInput{false, true, core.COLLIDE},
+			Expected: Expected{[]string{}, []string{"enter:wall", "stay:wall", "exit:wall"}},
+		},
+		{
+			Name:     getName,
+			Input:    TestInput{false, false, core.TRIGGER},
+			Expected: Expected{[]string{}, []string{"enter:wall", "stay:wall", "exit:wall"}},
+		},
+		{
+			// the layer matrix still decides whether a trigger is noticed at all
+			Name:     getName,
+			Input:    TestInput{true, false, core.IGNORE},
+			Expected: Expected{[]string{}, []string{}},
+		},
+	}
+
+	util.IterateTestCases(cases, t, func(testCase util.TestCase[TestInput, Expected]) {
+		collisionSys := NewCollisionSystem(quadtree.Rect{X: 0, Y: 0, W: 200, H: 200})
+		collisionSys.Layers().SetInteraction(core.PLAYER_LAYER, core.WALL_LAYER, testCase.Input.interaction)
+
+		rect := quadtree.Rect{X: 0, Y: 0, W: 10, H: 10}
+		wall := NewCollider(core.WALL_LAYER, "wall", rect, &collisionSys, &collisionSys, nil, nil)
+		wall.SetTrigger(testCase.Input.wallIsTrigger)
+		player := NewCollider(core.PLAYER_LAYER, "player", rect, &collisionSys, &collisionSys, nil, nil)
+		player.SetTrigger(testCase.Input.playerIsTrigger)
+
+		collisionEvents := make([]string, 0)
+		recordContactEvents(player, &collisionEvents)
+
+		triggerEvents := make([]string, 0)
+		player.AddTriggerEnterEvent(func(collision Collision) {
+			require.Equal(t, core.TRIGGER, collision.Interaction)
+			triggerEvents = append(triggerEvents, "enter:"+collision.Other.ID())
+		})
+		player.AddTriggerStayEvent(func(collision Collision) {
+			triggerEvents = append(triggerEvents, "stay:"+collision.Other.ID())
+		})
+		player.AddTriggerExitEvent(func(collision Collision) {
+			triggerEvents = append(triggerEvents, "exit:"+collision.Other.ID())
+		})
+
+		collisionSys.OnLoop()
+		collisionSys.OnLoop()
+		player.UpdatePos(50, 0)
+		collisionSys.OnLoop()
+
+		require.Equal(t, testCase.Expected.collisionEvents, collisionEvents)
+		require.Equal(t, testCase.Expected.triggerEvents, triggerEvents)
+	})
+}
+
+func TestDisabledColliderShouldNotBeCollidedWith(t *testing.T) {
+	collisionSys := NewCollisionSystem(quadtree.Rect{X: 0, Y: 0, W: 200, H: 200})
+	rect := quadtree.Rect{X: 0, Y: 0, W: 10, H: 10}
+	door := NewCollider(core.WALL_LAYER, "door", rect, &collisionSys, &collisionSys, nil, nil)
+	player := NewCollider(core.PLAYER_LAYER, "player", rect, &collisionSys, &collisionSys, nil, nil)
+
+	playerEvents := make([]string, 0)
+	doorEvents := make([]string, 0)
+	recordContactEvents(player, &playerEvents)
+	recordContactEvents(door, &doorEvents)
+
+	collisionSys.OnLoop()
+	door.Disable()
+	require.False(t, door.IsEnabled())
+	collisionSys.OnLoop()
+
+	require.Equal(t, []string{"enter:door", "exit:door"}, playerEvents)
+	require.Equal(t, []string{"enter:player"}, doorEvents)
+	require.Equal(t, []string{"player"}, ids(collisionSys.DetectCollisions(rect)))
+
+	// moving while disabled puts the collider back at its new rect once enabled
+	door.UpdatePos(50, 0)
+	door.Enable()
+	require.True(t, door.IsEnabled())
+
+	require.Equal(t, []string{"player"}, ids(collisionSys.DetectCollisions(rect)))
+	require.Equal(t, []string{"door"}, ids(collisionSys.DetectCollisions(quadtree.Rect{X: 50, Y: 0, W: 10, H: 10})))
+}
+
+func TestDeregisterObjectShouldRemoveDisabledCollider(t *testing.T) {
+	collisionSys := NewCollisionSystem(quadtree.Rect{X: 0, Y: 0, W: 200, H: 200})
+	rect := quadtree.Rect{X: 0, Y: 0, W: 10, H: 10}
+	hitbox := NewCollider(core.PLAYER_LAYER, "hitbox", rect, &collisionSys, &collisionSys, nil, nil)
+
+	hitbox.Disable()
+	collisionSys.DeregisterObject(hitbox)
+	hitbox.Enable()
+
+	require.Empty(t, collisionSys.DetectCollisions(rect))
+	require.Empty(t, collisionSys.colliders)
+	require.Empty(t, collisionSys.disabledColliders)
+}
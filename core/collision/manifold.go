@@ -0,0 +1,322 @@
+package collision
+
+import "math"
+
+// The contact between two colliders, which is the authoritative description of how they overlap
+// for resolving collisions, taking damage from a direction or placing effects
+type Manifold struct {
+	Collider *Collider
+	Other    *Collider
+
+	Normal     vec     // unit vector pointing from Collider towards Other
+	Depth      float32 // how far the colliders overlap along the normal, negative when there is a gap between them
+	Points     [2]vec  // where the colliders touch, only the first PointCount points are used
+	PointCount int
+}
+
+// Returns the manifold with the colliders swapped
+func (manifold Manifold) Flipped() Manifold {
+	manifold.Collider, manifold.Other = manifold.Other, manifold.Collider
+	manifold.Normal = manifold.Normal.Multiply(-1)
+	return manifold
+}
+
+// Computes the contact manifold between the two colliders at their current positions.
+// The manifold is also computed when the colliders do not overlap, in which case its depth is the
+// negative of the gap between them, so that movement can be stopped before reaching the other collider.
+func (collider *Collider) ContactWith(other *Collider) Manifold {
+	var box, otherBox [4]vec
+	a, b := collider.primitive(&box), other.primitive(&otherBox)
+
+	var manifold Manifold
+	switch {
+	case a.isPolygon && b.isPolygon:
+		manifold = polygonsManifold(a.polygon, b.polygon)
+	case a.isPolygon:
+		manifold = polygonCapsuleManifold(a.polygon, b.capsule)
+	case b.isPolygon:
+		manifold = polygonCapsuleManifold(b.polygon, a.capsule).Flipped()
+	default:
+		manifold = capsulesManifold(a.capsule, b.capsule)
+	}
+
+	manifold.Collider, manifold.Other = collider, other
+	return manifold
+}
+
+// Computes the contact manifold the collider would have with the other collider after moving by dist, without moving it
+func (collider *Collider) ContactAfter(dist vec, other *Collider) Manifold {
+	probe := *collider
+	probe.Pos = collider.Pos.Add(dist)
+	probe.Rect = probe.rectAt(probe.Pos)
+
+	manifold := probe.ContactWith(other)
+	manifold.Collider = collider
+	return manifold
+}
+
+// Returns how far the collider moves along dist before touching the other collider, as a fraction of dist,
+// and the normal from the collider towards the other collider where they touch. Returns false when they do not
+// touch within dist, or when they already overlap, which is left to resolving their contact.
+// The collider is swept the same as a cast, so it touches colliders however shallow the angle it moves in to them at.
+func (collider *Collider) TimeOfImpact(dist vec, other *Collider) (float32, vec, bool) {
+	distance := dist.Length()
+	if distance == 0 || collider.Overlaps(other) {
+		return 0, vec{}, false
+	}
+
+	probe := *collider
+	hit, ok := sweep(&probe, collider.Pos, dist.Multiply(1/distance), distance, other)
+	if !ok {
+		return 0, vec{}, false
+	}
+
+	return hit.Distance / distance, hit.Normal.Multiply(-1), true
+}
+
+// how much further than the separation of the faces of the first polygon the faces of the second must be separated
+// for the second to be used as the reference, so that equally separated faces consistently use the first polygon
+const referenceTolerance = 0.001
+
+// Uses the face of either polygon that is separated the most from the other polygon as the reference face,
+// and clips the face of the other polygon that faces it the most to the sides of the reference face for the contact points
+func polygonsManifold(a worldPolygon, b worldPolygon) Manifold {
+	separationA, faceA := maxFaceSeparation(a, b)
+	separationB, faceB := maxFaceSeparation(b, a)
+
+	reference, incident, face, separation, sign := a, b, faceA, separationA, float32(1)
+	if separationB > separationA+referenceTolerance {
+		reference, incident, face, separation, sign = b, a, faceB, separationB, -1
+	}
+
+	normal := reference.normal(face)
+	manifold := Manifold{Normal: normal.Multiply(sign), Depth: -separation}
+
+	// the face of the incident polygon whose normal is most opposite to the reference normal
+	incidentFace := 0
+	for i := range incident.points {
+		if incident.normal(i).Dot(normal) < incident.normal(incidentFace).Dot(normal) {
+			incidentFace = i
+		}
+	}
+
+	start, end, ok := clipToFace(reference, face, incident.point(incidentFace), incident.point(incidentFace+1))
+	if !ok {
+		// a corner meeting a corner, which touches at the incident vertex closest to the reference face
+		closest := incident.point(0)
+		for i := range incident.points {
+			if incident.point(i).Dot(normal) < closest.Dot(normal) {
+				closest = incident.point(i)
+			}
+		}
+
+		start, end = closest, closest
+	}
+
+	manifold.Points = [2]vec{start, end}
+	manifold.PointCount = 2
+	if start == end {
+		manifold.PointCount = 1
+	}
+
+	return manifold
+}
+
+// Returns the largest separation of a face of polygon from the other polygon along the face normal, and the face
+func maxFaceSeparation(polygon worldPolygon, other worldPolygon) (float32, int) {
+	maxSeparation, maxFace := float32(math.Inf(-1)), 0
+
+	for i := range polygon.points {
+		normal := polygon.normal(i)
+		vertex := polygon.point(i)
+
+		separation := float32(math.Inf(1))
+		for j := range other.points {
+			separation = min(separation, other.point(j).Sub(vertex).Dot(normal))
+		}
+
+		if separation > maxSeparation {
+			maxSeparation, maxFace = separation, i
+		}
+	}
+
+	return maxSeparation, maxFace
+}
+
+// Clips the segment from start to end to the region between the sides of the face of polygon.
+// Returns false when no part of the segment lies in the region.
+func clipToFace(polygon worldPolygon, face int, start vec, end vec) (vec, vec, bool) {
+	faceStart, faceEnd := polygon.point(face), polygon.point(face+1)
+	tangent := faceEnd.Sub(faceStart).Normalize()
+
+	lower, upper := faceStart.Dot(tangent), faceEnd.Dot(tangent)
+	startProj, endProj := start.Dot(tangent), end.Dot(tangent)
+
+	if startProj > endProj {
+		start, end = end, start
+		startProj, endProj = endProj, startProj
+	}
+
+	if endProj < lower || startProj > upper {
+		return vec{}, vec{}, false
+	}
+
+	direction := end.Sub(start)
+	length := endProj - startProj
+	if length > 0 {
+		if startProj < lower {
+			start = start.Add(direction.Multiply((lower - startProj) / length))
+		}
+
+		if endProj > upper {
+			end = end.Sub(direction.Multiply((endProj - upper) / length))
+		}
+	}
+
+	return start, end, true
+}
+
+// Uses the closest points between the two segments, with the contact point in the middle of the overlap
+func capsulesManifold(a worldCapsule, b worldCapsule) Manifold {
+	closestA, closestB := closestPointsOnSegments(a.a, a.b, b.a, b.b)
+	offset := closestB.Sub(closestA)
+	distance := offset.Length()
+
+	normal := vec{X: 0, Y: -1}
+	if distance > 0 {
+		normal = offset.Multiply(1 / distance)
+	} else if centers := b.a.Midpoint(b.b).Sub(a.a.Midpoint(a.b)); centers.Length() > 0 {
+		// the segments touch so use the direction between their centers instead
+		normal = centers.Normalize()
+	}
+
+	depth := a.radius + b.radius - distance
+	return Manifold{
+		Normal:     normal,
+		Depth:      depth,
+		Points:     [2]vec{closestA.Add(normal.Multiply(a.radius - depth/2))},
+		PointCount: 1,
+	}
+}
+
+// Finds the axis the polygon and capsule are separated the most along, out of the face normals of the polygon,
+// the normals of the capsule segment and the directions from each polygon vertex to the capsule segment.
+// The normal points from the polygon towards the capsule.
+func polygonCapsuleManifold(polygon worldPolygon, capsule worldCapsule) Manifold {
+	separation, normal, face := float32(math.Inf(-1)), vec{}, -1
+
+	consider := func(axis vec, axisFace int) {
+		if axisSeparation := capsuleSeparation(polygon, capsule, axis); axisSeparation > separation {
+			separation, normal, face = axisSeparation, axis, axisFace
+		}
+	}
+
+	for i := range polygon.points {
+		consider(polygon.normal(i), i)
+	}
+
+	if segment := capsule.b.Sub(capsule.a); segment.Length() > 0 {
+		segmentNormal := vec{X: -segment.Y, Y: segment.X}.Normalize()
+		consider(segmentNormal, -1)
+		consider(segmentNormal.Multiply(-1), -1)
+	}
+
+	for i := range polygon.points {
+		vertex := polygon.point(i)
+		if toSegment := closestPointOnSegment(vertex, capsule.a, capsule.b).Sub(vertex); toSegment.Length() > 0 {
+			consider(toSegment.Normalize(), -1)
+		}
+	}
+
+	manifold := Manifold{Normal: normal, Depth: -separation}
+
+	if face == -1 {
+		// the deepest point of the polygon in to the capsule
+		deepest := polygon.point(0)
+		for i := range polygon.points {
+			if polygon.point(i).Dot(normal) > deepest.Dot(normal) {
+				deepest = polygon.point(i)
+			}
+		}
+
+		manifold.Points[0], manifold.PointCount = deepest, 1
+		return manifold
+	}
+
+	// the capsule rests against a face, so use the ends of its segment that are on the face
+	start, end, ok := clipToFace(polygon, face, capsule.a, capsule.b)
+	if !ok {
+		start, end = capsule.a, capsule.b
+	}
+
+	radius := normal.Multiply(capsule.radius)
+	startDepth, endDepth := start.Dot(normal), end.Dot(normal)
+
+	switch {
+	case start != end && math.Abs(float64(startDepth-endDepth)) <= referenceTolerance:
+		manifold.Points, manifold.PointCount = [2]vec{start.Sub(radius), end.Sub(radius)}, 2
+	case startDepth <= endDepth:
+		manifold.Points[0], manifold.PointCount = start.Sub(radius), 1
+	default:
+		manifold.Points[0], manifold.PointCount = end.Sub(radius), 1
+	}
+
+	return manifold
+}
+
+// how far the capsule is separated from the polygon along the axis pointing from the polygon to the capsule
+func capsuleSeparation(polygon worldPolygon, capsule worldCapsule, axis vec) float32 {
+	_, polygonMax := project(polygon, axis)
+	capsuleMin := min(capsule.a.Dot(axis), capsule.b.Dot(axis)) - capsule.radius
+
+	return capsuleMin - polygonMax
+}
+
+// the outward unit normal of the face from point i to point i+1
+func (polygon worldPolygon) normal(i int) vec {
+	edge := polygon.point(i + 1).Sub(polygon.point(i))
+	normal := vec{X: -edge.Y, Y: edge.X}.Normalize()
+
+	// the points can be in either winding order, so flip normals that point towards the inside
+	if normal.Dot(polygon.point(i).Sub(polygon.center)) < 0 {
+		return normal.Multiply(-1)
+	}
+
+	return normal
+}
+
+// Returns the closest points on the two segments to each other
+func closestPointsOnSegments(p1 vec, q1 vec, p2 vec, q2 vec) (vec, vec) {
+	if segmentsCross(p1, q1, p2, q2) {
+		d1, d2 := q1.Sub(p1), q2.Sub(p2)
+		crossing := p1.Add(d1.Multiply(p2.Sub(p1).Cross(d2) / d1.Cross(d2)))
+		return crossing, crossing
+	}
+
+	candidates := [4][2]vec{
+		{p1, closestPointOnSegment(p1, p2, q2)},
+		{q1, closestPointOnSegment(q1, p2, q2)},
+		{closestPointOnSegment(p2, p1, q1), p2},
+		{closestPointOnSegment(q2, p1, q1), q2},
+	}
+
+	closest := candidates[0]
+	for _, candidate := range candidates[1:] {
+		if candidate[0].Sub(candidate[1]).LengthSquared() < closest[0].Sub(closest[1]).LengthSquared() {
+			closest = candidate
+		}
+	}
+
+	return closest[0], closest[1]
+}
+
+func closestPointOnSegment(point vec, a vec, b vec) vec {
+	segment := b.Sub(a)
+	lengthSquared := segment.LengthSquared()
+	if lengthSquared == 0 {
+		return a
+	}
+
+	t := min(max(point.Sub(a).Dot(segment)/lengthSquared, 0), 1)
+	return a.Add(segment.Multiply(t))
+}
@@ -0,0 +1,179 @@
+package collision
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/TheRaizer/GolangGame/core"
+	"github.com/TheRaizer/GolangGame/util"
+	"github.com/TheRaizer/GolangGame/util/datastructures/quadtree"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContactWith(t *testing.T) {
+	type TestInput struct {
+		a placedShape
+		b placedShape
+	}
+
+	const NAME string = "should compute the contact of %+v with %+v"
+	getName := func(input TestInput) string {
+		return fmt.Sprintf(NAME, input.a, input.b)
+	}
+
+	circle := Circle{vec{}, 5}
+	capsule := Capsule{vec{X: 0, Y: 0}, vec{X: 20, Y: 0}, 2}
+
+	cases := []util.TestCase[TestInput, Manifold]{
+		// overlapping rects touch along the face of the other rect
+		{
+			Name:     getName,
+			Input:    TestInput{placedShape{vec{X: 0, Y: 0}, nil}, placedShape{vec{X: 8, Y: 0}, nil}},
+			Expected: Manifold{Normal: vec{X: 1, Y: 0}, Depth: 2, Points: [2]vec{{X: 8, Y: 0}, {X: 8, Y: 10}}, PointCount: 2},
+		},
+		{
+			Name:     getName,
+			Input:    TestInput{placedShape{vec{X: 0, Y: 8}, nil}, placedShape{vec{X: 0, Y: 0}, nil}},
+			Expected: Manifold{Normal: vec{X: 0, Y: -1}, Depth: 2, Points: [2]vec{{X: 0, Y: 10}, {X: 10, Y: 10}}, PointCount: 2},
+		},
+		// separated rects have the gap as a negative depth
+		{
+			Name:     getName,
+			Input:    TestInput{placedShape{vec{X: 0, Y: 0}, nil}, placedShape{vec{X: 15, Y: 3}, nil}},
+			Expected: Manifold{Normal: vec{X: 1, Y: 0}, Depth: -5, Points: [2]vec{{X: 15, Y: 3}, {X: 15, Y: 10}}, PointCount: 2},
+		},
+		// a polygon whose closest face is past the end of the reference face touches at its closest corner
+		{
+			Name: getName,
+			Input: TestInput{
+				placedShape{vec{X: 0, Y: 0}, nil},
+				placedShape{vec{X: 13, Y: 11}, NewPolygon(vec{X: 0, Y: 0}, vec{X: 10, Y: 3}, vec{X: 3, Y: 10})},
+			},
+			Expected: Manifold{Normal: vec{X: 1, Y: 0}, Depth: -3, Points: [2]vec{{X: 13, Y: 11}, {X: 13, Y: 11}}, PointCount: 1},
+		},
+		// circles touch at a single point halfway through their overlap
+		{
+			Name:     getName,
+			Input:    TestInput{placedShape{vec{X: 0, Y: 0}, circle}, placedShape{vec{X: 8, Y: 0}, circle}},
+			Expected: Manifold{Normal: vec{X: 1, Y: 0}, Depth: 2, Points: [2]vec{{X: 4, Y: 0}}, PointCount: 1},
+		},
+		// a circle against the face of a rect touches at a single point on the circle
+		{
+			Name:     getName,
+			Input:    TestInput{placedShape{vec{X: 0, Y: 0}, nil}, placedShape{vec{X: 14, Y: 5}, circle}},
+			Expected: Manifold{Normal: vec{X: 1, Y: 0}, Depth: 1, Points: [2]vec{{X: 9, Y: 5}}, PointCount: 1},
+		},
+		{
+			Name:     getName,
+			Input:    TestInput{placedShape{vec{X: 14, Y: 5}, circle}, placedShape{vec{X: 0, Y: 0}, nil}},
+			Expected: Manifold{Normal: vec{X: -1, Y: 0}, Depth: 1, Points: [2]vec{{X: 9, Y: 5}}, PointCount: 1},
+		},
+		// a capsule lying on a rect touches along the part of the face under it
+		{
+			Name:     getName,
+			Input:    TestInput{placedShape{vec{X: 0, Y: 0}, capsule}, placedShape{vec{X: 5, Y: 1}, nil}},
+			Expected: Manifold{Normal: vec{X: 0, Y: 1}, Depth: 1, Points: [2]vec{{X: 5, Y: 2}, {X: 15, Y: 2}}, PointCount: 2},
+		},
+	}
+
+	util.IterateTestCases(cases, t, func(testCase util.TestCase[TestInput, Manifold]) {
+		a := newTestCollider(testCase.Input.a)
+		b := newTestCollider(testCase.Input.b)
+
+		manifold := a.ContactWith(b)
+		require.Same(t, a, manifold.Collider)
+		require.Same(t, b, manifold.Other)
+
+		manifold.Collider, manifold.Other = nil, nil
+		require.Equal(t, testCase.Expected, manifold)
+	})
+}
+
+func TestContactWithShouldFindTheCornerOfARect(t *testing.T) {
+	rect := newTestCollider(placedShape{vec{X: 0, Y: 0}, nil})
+	circle := newTestCollider(placedShape{vec{X: 12, Y: 12}, Circle{vec{}, 3}})
+
+	manifold := rect.ContactWith(circle)
+
+	require.InDelta(t, 1/sqrt2, manifold.Normal.X, 1e-5)
+	require.InDelta(t, 1/sqrt2, manifold.Normal.Y, 1e-5)
+	require.InDelta(t, 3-2*sqrt2, manifold.Depth, 1e-5)
+	require.Equal(t, 1, manifold.PointCount)
+	require.Equal(t, vec{X: 10, Y: 10}, manifold.Points[0])
+}
+
+const sqrt2 = 1.4142135
+
+func TestTimeOfImpact(t *testing.T) {
+	type TestInput struct {
+		a    placedShape
+		b    placedShape
+		dist vec
+	}
+
+	type TestExpected struct {
+		impact float32
+		normal vec
+		ok     bool
+	}
+
+	const NAME string = "should find the time of impact of %+v"
+	getName := func(input TestInput) string {
+		return fmt.Sprintf(NAME, input)
+	}
+
+	circle := Circle{vec{X: 5, Y: 5}, 5}
+
+	cases := []util.TestCase[TestInput, TestExpected]{
+		// rects are swept as boxes
+		{Name: getName, Input: TestInput{placedShape{vec{}, nil}, placedShape{vec{X: 20, Y: 0}, nil}, vec{X: 40, Y: 0}}, Expected: TestExpected{0.25, vec{X: 1, Y: 0}, true}},
+		{Name: getName, Input: TestInput{placedShape{vec{}, nil}, placedShape{vec{X: 20, Y: 20}, nil}, vec{X: 20, Y: 40}}, Expected: TestExpected{0.5, vec{X: 1, Y: 0}, true}},
+		{Name: getName, Input: TestInput{placedShape{vec{}, nil}, placedShape{vec{X: 20, Y: 0}, nil}, vec{X: 5, Y: 0}}, Expected: TestExpected{}},
+		{Name: getName, Input: TestInput{placedShape{vec{}, nil}, placedShape{vec{X: 0, Y: 20}, nil}, vec{X: 40, Y: 0}}, Expected: TestExpected{}},
+		// overlapping colliders are left to their contact
+		{Name: getName, Input: TestInput{placedShape{vec{}, nil}, placedShape{vec{X: 5, Y: 0}, nil}, vec{X: 40, Y: 0}}, Expected: TestExpected{}},
+		// shapes are swept by conservative advancement
+		{Name: getName, Input: TestInput{placedShape{vec{}, circle}, placedShape{vec{X: 20, Y: 0}, nil}, vec{X: 40, Y: 0}}, Expected: TestExpected{0.25, vec{X: 1, Y: 0}, true}},
+	}
+
+	util.IterateTestCases(cases, t, func(testCase util.TestCase[TestInput, TestExpected]) {
+		a := newTestCollider(testCase.Input.a)
+		b := newTestCollider(testCase.Input.b)
+
+		impact, normal, ok := a.TimeOfImpact(testCase.Input.dist, b)
+		require.Equal(t, testCase.Expected.ok, ok)
+		require.InDelta(t, testCase.Expected.impact, impact, castTolerance)
+		require.InDelta(t, testCase.Expected.normal.X, normal.X, 1e-5)
+		require.InDelta(t, testCase.Expected.normal.Y, normal.Y, 1e-5)
+
+		// the collider is not moved
+		require.Equal(t, testCase.Input.a.pos, a.Pos)
+	})
+}
+
+func TestFlippedShouldSwapColliders(t *testing.T) {
+	a := newTestCollider(placedShape{vec{X: 0, Y: 0}, nil})
+	b := newTestCollider(placedShape{vec{X: 8, Y: 0}, nil})
+
+	manifold := a.ContactWith(b).Flipped()
+
+	require.Same(t, b, manifold.Collider)
+	require.Same(t, a, manifold.Other)
+	require.Equal(t, vec{X: -1, Y: 0}, manifold.Normal)
+	require.Equal(t, float32(2), manifold.Depth)
+}
+
+func TestDetectContactsShouldSkipIgnoredLayersAndSelf(t *testing.T) {
+	collisionSys := NewCollisionSystem(quadtree.Rect{X: 0, Y: 0, W: 200, H: 200})
+	player := NewCollider(core.PLAYER_LAYER, "player", quadtree.Rect{X: 0, Y: 0, W: 10, H: 10}, &collisionSys, &collisionSys, nil, nil)
+	wall := NewCollider(core.WALL_LAYER, "wall", quadtree.Rect{X: 12, Y: 0, W: 10, H: 10}, &collisionSys, &collisionSys, nil, nil)
+	// players ignore each other
+	NewCollider(core.PLAYER_LAYER, "other_player", quadtree.Rect{X: 12, Y: 0, W: 10, H: 10}, &collisionSys, &collisionSys, nil, nil)
+
+	// the rect the player would move to reaches the wall and other player, which are not overlapped yet
+	contacts := collisionSys.DetectContacts(player, quadtree.Rect{X: 5, Y: 0, W: 10, H: 10}, nil)
+
+	require.Len(t, contacts, 1)
+	require.Same(t, wall, contacts[0].Other)
+	require.Equal(t, float32(-2), contacts[0].Depth)
+}
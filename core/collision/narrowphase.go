@@ -0,0 +1,196 @@
+package collision
+
+import "github.com/TheRaizer/GolangGame/util"
+
+// Narrow phase tests between the shapes of colliders whose bounding boxes the broad phase found to overlap.
+// Every shape is tested as either a convex polygon, using the separating axis theorem, or as a capsule,
+// using the closest points between it and the other shape. Circles are capsules whose segment is a single point.
+// Shapes that only touch do not overlap, the same as rects.
+
+type vec = util.Vec2[float32]
+
+// a convex polygon placed in the world at offset
+type worldPolygon struct {
+	points []vec
+	offset vec
+	center vec // the mean of the points in the world, which lies inside the polygon
+}
+
+// a capsule placed in the world
+type worldCapsule struct {
+	a, b   vec
+	radius float32
+}
+
+type primitive struct {
+	isPolygon bool
+	polygon   worldPolygon
+	capsule   worldCapsule
+}
+
+// Returns whether the shapes of the two colliders overlap
+func (collider *Collider) Overlaps(other *Collider) bool {
+	if !collider.Rect.Intersects(other.Rect) {
+		return false
+	}
+
+	if collider.shape == nil && other.shape == nil {
+		return true
+	}
+
+	// storage for the points of rect colliders, kept here so that testing them does not allocate
+	var box, otherBox [4]vec
+	return primitivesOverlap(collider.primitive(&box), other.primitive(&otherBox))
+}
+
+func (collider *Collider) primitive(box *[4]vec) primitive {
+	origin := collider.Pos.Add(collider.offset)
+
+	switch shape := collider.shape.(type) {
+	case Circle:
+		center := origin.Add(shape.Center)
+		return primitive{capsule: worldCapsule{center, center, shape.Radius}}
+	case Capsule:
+		return primitive{capsule: worldCapsule{origin.Add(shape.A), origin.Add(shape.B), shape.Radius}}
+	case Polygon:
+		return primitive{isPolygon: true, polygon: newWorldPolygon(shape.Points, origin)}
+	case nil:
+		rect := collider.Rect
+		*box = [4]vec{{X: rect.X, Y: rect.Y}, {X: rect.Right(), Y: rect.Y}, {X: rect.Right(), Y: rect.Bottom()}, {X: rect.X, Y: rect.Bottom()}}
+		return primitive{isPolygon: true, polygon: worldPolygon{box[:], vec{}, vec{X: rect.X + rect.W/2, Y: rect.Y + rect.H/2}}}
+	}
+
+	panic("unsupported collider shape")
+}
+
+func primitivesOverlap(a primitive, b primitive) bool {
+	switch {
+	case a.isPolygon && b.isPolygon:
+		return polygonsOverlap(a.polygon, b.polygon)
+	case a.isPolygon:
+		return polygonCapsuleOverlap(a.polygon, b.capsule)
+	case b.isPolygon:
+		return polygonCapsuleOverlap(b.polygon, a.capsule)
+	default:
+		return capsulesOverlap(a.capsule, b.capsule)
+	}
+}
+
+func newWorldPolygon(points []vec, offset vec) worldPolygon {
+	var center vec
+	for _, point := range points {
+		center = center.Add(point)
+	}
+
+	return worldPolygon{points, offset, center.Multiply(1 / float32(len(points))).Add(offset)}
+}
+
+func (polygon worldPolygon) point(i int) vec {
+	return polygon.points[i%len(polygon.points)].Add(polygon.offset)
+}
+
+// Separating axis theorem, two convex polygons do not overlap when their projections on to the normal
+// of any of their edges do not overlap
+func polygonsOverlap(a worldPolygon, b worldPolygon) bool {
+	return !hasSeparatingEdge(a, b) && !hasSeparatingEdge(b, a)
+}
+
+func hasSeparatingEdge(polygon worldPolygon, other worldPolygon) bool {
+	for i := range polygon.points {
+		edge := polygon.point(i + 1).Sub(polygon.point(i))
+		axis := vec{X: -edge.Y, Y: edge.X}
+
+		minA, maxA := project(polygon, axis)
+		minB, maxB := project(other, axis)
+		if maxA <= minB || maxB <= minA {
+			return true
+		}
+	}
+
+	return false
+}
+
+func project(polygon worldPolygon, axis vec) (float32, float32) {
+	minProj := polygon.point(0).Dot(axis)
+	maxProj := minProj
+
+	for i := 1; i < len(polygon.points); i++ {
+		proj := polygon.point(i).Dot(axis)
+		minProj, maxProj = min(minProj, proj), max(maxProj, proj)
+	}
+
+	return minProj, maxProj
+}
+
+func capsulesOverlap(a worldCapsule, b worldCapsule) bool {
+	radius := a.radius + b.radius
+	return segmentsDistanceSquared(a.a, a.b, b.a, b.b) < radius*radius
+}
+
+// A capsule overlaps a polygon when its segment starts inside the polygon,
+// or when the closest point between its segment and the edges of the polygon is within its radius
+func polygonCapsuleOverlap(polygon worldPolygon, capsule worldCapsule) bool {
+	if containsPoint(polygon, capsule.a) {
+		return true
+	}
+
+	radiusSquared := capsule.radius * capsule.radius
+	for i := range polygon.points {
+		if segmentsDistanceSquared(polygon.point(i), polygon.point(i+1), capsule.a, capsule.b) < radiusSquared {
+			return true
+		}
+	}
+
+	// a capsule without a radius can still cross straight through the polygon
+	return capsule.radius == 0 && segmentCrossesPolygon(polygon, capsule.a, capsule.b)
+}
+
+func segmentCrossesPolygon(polygon worldPolygon, a vec, b vec) bool {
+	for i := range polygon.points {
+		if segmentsCross(polygon.point(i), polygon.point(i+1), a, b) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// whether the point is strictly inside the polygon, which is when it is on the same side of every edge
+func containsPoint(polygon worldPolygon, point vec) bool {
+	var sign float32
+	for i := range polygon.points {
+		turn := polygon.point(i + 1).Sub(polygon.point(i)).Cross(point.Sub(polygon.point(i)))
+		if turn == 0 || (sign != 0 && (turn > 0) != (sign > 0)) {
+			return false
+		}
+
+		sign = turn
+	}
+
+	return true
+}
+
+func segmentsDistanceSquared(p1 vec, q1 vec, p2 vec, q2 vec) float32 {
+	if segmentsCross(p1, q1, p2, q2) {
+		return 0
+	}
+
+	return min(
+		pointSegmentDistanceSquared(p1, p2, q2),
+		pointSegmentDistanceSquared(q1, p2, q2),
+		pointSegmentDistanceSquared(p2, p1, q1),
+		pointSegmentDistanceSquared(q2, p1, q1),
+	)
+}
+
+// whether the segments cross each other at a single point that is not one of their ends
+func segmentsCross(p1 vec, q1 vec, p2 vec, q2 vec) bool {
+	d1 := q1.Sub(p1)
+	d2 := q2.Sub(p2)
+
+	return d1.Cross(p2.Sub(p1))*d1.Cross(q2.Sub(p1)) < 0 && d2.Cross(p1.Sub(p2))*d2.Cross(q1.Sub(p2)) < 0
+}
+
+func pointSegmentDistanceSquared(point vec, a vec, b vec) float32 {
+	return point.Sub(closestPointOnSegment(point, a, b)).LengthSquared()
+}
@@ -0,0 +1,134 @@
+package collision
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/TheRaizer/GolangGame/util"
+	"github.com/TheRaizer/GolangGame/util/datastructures/quadtree"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// a shape placed at a position, a nil shape is a rect collider of size 10x10
+type placedShape struct {
+	pos   vec
+	shape Shape
+}
+
+func newTestCollider(placed placedShape) *Collider {
+	mockSys := &MockCollisionSystem{}
+	mockSys.On("RegisterObject", mock.Anything)
+
+	if placed.shape == nil {
+		return NewCollider(0, "rect", quadtree.Rect{X: placed.pos.X, Y: placed.pos.Y, W: 10, H: 10}, mockSys, mockSys, nil, nil)
+	}
+
+	return NewShapeCollider(0, "shape", placed.pos, placed.shape, mockSys, mockSys, nil, nil)
+}
+
+func TestOverlaps(t *testing.T) {
+	type TestInput struct {
+		a placedShape
+		b placedShape
+	}
+
+	const NAME string = "should return whether %+v overlaps %+v"
+	getName := func(input TestInput) string {
+		return fmt.Sprintf(NAME, input.a, input.b)
+	}
+
+	circle := Circle{vec{}, 5}
+	capsule := Capsule{vec{X: 0, Y: 0}, vec{X: 20, Y: 0}, 2}
+	triangle := NewPolygon(vec{X: 0, Y: 0}, vec{X: 10, Y: 0}, vec{X: 0, Y: 10})
+	diamond := NewPolygon(vec{X: 5, Y: 0}, vec{X: 10, Y: 5}, vec{X: 5, Y: 10}, vec{X: 0, Y: 5})
+
+	cases := []util.TestCase[TestInput, bool]{
+		// circle and circle
+		{Name: getName, Input: TestInput{placedShape{vec{X: 0, Y: 0}, circle}, placedShape{vec{X: 9, Y: 0}, circle}}, Expected: true},
+		{Name: getName, Input: TestInput{placedShape{vec{X: 0, Y: 0}, circle}, placedShape{vec{X: 10, Y: 0}, circle}}, Expected: false},
+		// the bounding boxes overlap but the circles do not
+		{Name: getName, Input: TestInput{placedShape{vec{X: 0, Y: 0}, circle}, placedShape{vec{X: 8, Y: 8}, circle}}, Expected: false},
+
+		// circle and rect
+		{Name: getName, Input: TestInput{placedShape{vec{X: 14, Y: 5}, circle}, placedShape{vec{X: 0, Y: 0}, nil}}, Expected: true},
+		{Name: getName, Input: TestInput{placedShape{vec{X: 14, Y: 14}, circle}, placedShape{vec{X: 0, Y: 0}, nil}}, Expected: false},
+		{Name: getName, Input: TestInput{placedShape{vec{X: 5, Y: 5}, Circle{vec{}, 1}}, placedShape{vec{X: 0, Y: 0}, nil}}, Expected: true},
+
+		// polygon and rect
+		{Name: getName, Input: TestInput{placedShape{vec{X: 0, Y: 0}, triangle}, placedShape{vec{X: 4, Y: 4}, nil}}, Expected: true},
+		{Name: getName, Input: TestInput{placedShape{vec{X: 0, Y: 0}, triangle}, placedShape{vec{X: 6, Y: 6}, nil}}, Expected: false},
+
+		// polygon and polygon
+		{Name: getName, Input: TestInput{placedShape{vec{X: 0, Y: 0}, diamond}, placedShape{vec{X: 8, Y: 0}, diamond}}, Expected: true},
+		{Name: getName, Input: TestInput{placedShape{vec{X: 0, Y: 0}, diamond}, placedShape{vec{X: 6, Y: 6}, diamond}}, Expected: false},
+		{Name: getName, Input: TestInput{placedShape{vec{X: 0, Y: 0}, diamond}, placedShape{vec{X: 2, Y: 2}, triangle}}, Expected: true},
+
+		// polygon and circle
+		{Name: getName, Input: TestInput{placedShape{vec{X: 0, Y: 0}, triangle}, placedShape{vec{X: 8, Y: 8}, Circle{vec{}, 2}}}, Expected: false},
+		{Name: getName, Input: TestInput{placedShape{vec{X: 0, Y: 0}, triangle}, placedShape{vec{X: 6, Y: 6}, Circle{vec{}, 2}}}, Expected: true},
+		{Name: getName, Input: TestInput{placedShape{vec{X: 0, Y: 0}, triangle}, placedShape{vec{X: 2, Y: 2}, Circle{vec{}, 1}}}, Expected: true},
+
+		// capsule and everything
+		{Name: getName, Input: TestInput{placedShape{vec{X: 0, Y: 0}, capsule}, placedShape{vec{X: 10, Y: 6}, circle}}, Expected: true},
+		{Name: getName, Input: TestInput{placedShape{vec{X: 0, Y: 0}, capsule}, placedShape{vec{X: 10, Y: 8}, circle}}, Expected: false},
+		{Name: getName, Input: TestInput{placedShape{vec{X: 0, Y: 0}, capsule}, placedShape{vec{X: 23, Y: -1}, circle}}, Expected: true},
+		{Name: getName, Input: TestInput{placedShape{vec{X: 0, Y: 0}, capsule}, placedShape{vec{X: 5, Y: 1}, nil}}, Expected: true},
+		{Name: getName, Input: TestInput{placedShape{vec{X: 0, Y: 0}, capsule}, placedShape{vec{X: 5, Y: 3}, nil}}, Expected: false},
+		{Name: getName, Input: TestInput{placedShape{vec{X: 0, Y: 0}, capsule}, placedShape{vec{X: 10, Y: -5}, Capsule{vec{}, vec{X: 0, Y: 10}, 1}}}, Expected: true},
+		{Name: getName, Input: TestInput{placedShape{vec{X: 0, Y: 0}, capsule}, placedShape{vec{X: 24, Y: -5}, Capsule{vec{}, vec{X: 0, Y: 10}, 1}}}, Expected: false},
+		// a capsule fully inside a large polygon
+		{Name: getName, Input: TestInput{placedShape{vec{X: 0, Y: 0}, capsule}, placedShape{vec{X: -50, Y: -50}, NewPolygon(vec{}, vec{X: 100}, vec{X: 100, Y: 100}, vec{Y: 100})}}, Expected: true},
+	}
+
+	util.IterateTestCases(cases, t, func(testCase util.TestCase[TestInput, bool]) {
+		a := newTestCollider(testCase.Input.a)
+		b := newTestCollider(testCase.Input.b)
+
+		require.Equal(t, testCase.Expected, a.Overlaps(b))
+		require.Equal(t, testCase.Expected, b.Overlaps(a))
+	})
+}
+
+func TestOverlapsShouldNotAllocate(t *testing.T) {
+	rect := newTestCollider(placedShape{vec{X: 0, Y: 0}, nil})
+	triangle := newTestCollider(placedShape{vec{X: 2, Y: 2}, NewPolygon(vec{X: 0, Y: 0}, vec{X: 10, Y: 0}, vec{X: 0, Y: 10})})
+	circle := newTestCollider(placedShape{vec{X: 5, Y: 5}, Circle{vec{}, 3}})
+
+	allocs := testing.AllocsPerRun(10, func() {
+		rect.Overlaps(triangle)
+		rect.Overlaps(circle)
+		triangle.Overlaps(circle)
+	})
+	require.Zero(t, allocs)
+}
+
+func TestOnLoopShouldOnlyReportOverlappingShapes(t *testing.T) {
+	collisionSys := NewCollisionSystem(quadtree.Rect{X: 0, Y: 0, W: 200, H: 200})
+	// a wall in the corner of the bounding box of the ball, which the ball does not reach
+	NewCollider(0, "corner", quadtree.Rect{X: 0, Y: 0, W: 2, H: 2}, &collisionSys, &collisionSys, nil, nil)
+	NewCollider(0, "side", quadtree.Rect{X: 0, Y: 8, W: 3, H: 3}, &collisionSys, &collisionSys, nil, nil)
+
+	ball := NewShapeCollider(1, "ball", vec{X: 10, Y: 10}, Circle{vec{}, 10}, &collisionSys, &collisionSys, nil, nil)
+	var collided []string
+	ball.AddCollisionEvent(func(els []quadtree.QuadElement) {
+		collided = ids(els)
+	})
+
+	collisionSys.OnLoop()
+	require.Equal(t, []string{"side"}, collided)
+}
+
+func TestSetShapeShouldUpdateBoundingBox(t *testing.T) {
+	collisionSys := NewCollisionSystem(quadtree.Rect{X: 0, Y: 0, W: 200, H: 200})
+	bar := NewShapeCollider(0, "bar", vec{X: 50, Y: 50}, Capsule{vec{X: -20}, vec{X: 20}, 2}, &collisionSys, &collisionSys, nil, nil)
+	require.Equal(t, quadtree.Rect{X: 28, Y: 48, W: 44, H: 4}, bar.Rect)
+
+	bar.SetShape(Capsule{vec{Y: -20}, vec{Y: 20}, 2})
+	require.Equal(t, quadtree.Rect{X: 48, Y: 28, W: 4, H: 44}, bar.Rect)
+	require.Equal(t, []string{"bar"}, ids(collisionSys.DetectCollisions(quadtree.Rect{X: 49, Y: 29, W: 1, H: 1})))
+
+	bar.UpdatePos(10, 0)
+	require.Equal(t, quadtree.Rect{X: 58, Y: 28, W: 4, H: 44}, bar.Rect)
+	require.Empty(t, collisionSys.DetectCollisions(quadtree.Rect{X: 49, Y: 29, W: 1, H: 1}))
+}
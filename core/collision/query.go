@@ -0,0 +1,219 @@
+package collision
+
+import (
+	"math"
+
+	"github.com/TheRaizer/GolangGame/util"
+	"github.com/TheRaizer/GolangGame/util/datastructures/quadtree"
+)
+
+// Scene queries look for the registered colliders in an area or along a cast, for gameplay code such as line of sight,
+// weapons and cameras. They test the shapes of the colliders the same as OnLoop, and see static and dynamic colliders
+// alike, but never disabled colliders.
+
+// a mask with the bit of every layer set
+const ALL_LAYERS uint32 = math.MaxUint32
+
+// Narrows down the colliders a scene query can return. The zero value includes no colliders, as a zero mask
+// includes no layers, so queries for every layer pass ALL_LAYERS rather than leaving the mask out.
+type QueryFilter struct {
+	Mask           uint32      // the layers to include, such as from LayerMask or ALL_LAYERS
+	Ignore         []*Collider // colliders to leave out, such as the colliders of the game object making the query
+	IgnoreTriggers bool
+}
+
+// A collider found by a cast
+type Hit struct {
+	Collider *Collider
+	Distance float32            // how far the cast travelled before touching the collider, 0 when it started overlapping it
+	Point    util.Vec2[float32] // where the cast touched the collider
+	Normal   util.Vec2[float32] // the surface normal of the collider where it was touched, pointing out of the collider
+}
+
+// how close a cast has to come to a collider to touch it
+const castTolerance = 0.01
+
+func (filter QueryFilter) includes(collider *Collider) bool {
+	if filter.Mask&(1<<collider.Layer()) == 0 || (filter.IgnoreTriggers && collider.isTrigger) {
+		return false
+	}
+
+	for _, ignored := range filter.Ignore {
+		if ignored == collider {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Appends the colliders overlapping the rect to buf
+func (collisionSys *CollisionSystem) OverlapBox(rect quadtree.Rect, filter QueryFilter, buf []*Collider) []*Collider {
+	probe := Collider{Rect: rect}
+	return collisionSys.overlap(&probe, filter, buf)
+}
+
+// Appends the colliders overlapping the circle to buf
+func (collisionSys *CollisionSystem) OverlapCircle(
+	center util.Vec2[float32],
+	radius float32,
+	filter QueryFilter,
+	buf []*Collider,
+) []*Collider {
+	circle := Circle{Radius: radius}
+	probe := Collider{Rect: circle.Bounds(center), shape: circle}
+	probe.Pos = center
+
+	return collisionSys.overlap(&probe, filter, buf)
+}
+
+func (collisionSys *CollisionSystem) overlap(probe *Collider, filter QueryFilter, buf []*Collider) []*Collider {
+	collisionSys.sceneQueryBuf = collisionSys.layers().QueryAppend(probe.Rect, nil, collisionSys.sceneQueryBuf[:0])
+
+	for _, el := range collisionSys.sceneQueryBuf {
+		other, ok := collisionSys.collider(el.Id)
+		if ok && filter.includes(other) && probe.Overlaps(other) {
+			buf = append(buf, other)
+		}
+	}
+
+	return buf
+}
+
+// Returns the closest collider the ray from origin in the direction hits within maxDistance,
+// and false when it does not hit any collider
+func (collisionSys *CollisionSystem) Raycast(
+	origin util.Vec2[float32],
+	direction util.Vec2[float32],
+	maxDistance float32,
+	filter QueryFilter,
+) (Hit, bool) {
+	// a ray is a circle without a radius
+	probe := Collider{shape: Circle{}}
+	probe.Pos = origin
+
+	return collisionSys.cast(&probe, direction, maxDistance, filter)
+}
+
+// Returns the closest collider that the rect hits when moved in the direction by up to maxDistance,
+// and false when it does not hit any collider
+func (collisionSys *CollisionSystem) BoxCast(
+	rect quadtree.Rect,
+	direction util.Vec2[float32],
+	maxDistance float32,
+	filter QueryFilter,
+) (Hit, bool) {
+	probe := Collider{Rect: rect}
+	probe.Pos = vec{X: rect.X, Y: rect.Y}
+
+	return collisionSys.cast(&probe, direction, maxDistance, filter)
+}
+
+// Sweeps the probe from its position along the direction, and returns the closest collider it hits
+func (collisionSys *CollisionSystem) cast(probe *Collider, direction vec, maxDistance float32, filter QueryFilter) (Hit, bool) {
+	if direction.Length() == 0 {
+		panic("cannot cast without a direction")
+	}
+
+	start := probe.Pos
+	direction = direction.Normalize()
+
+	startRect := probe.rectAt(start)
+	swept := startRect.Union(probe.rectAt(start.Add(direction.Multiply(maxDistance))))
+	collisionSys.sceneQueryBuf = collisionSys.layers().QueryAppend(swept, nil, collisionSys.sceneQueryBuf[:0])
+
+	closest, found := Hit{}, false
+	for _, el := range collisionSys.sceneQueryBuf {
+		other, ok := collisionSys.collider(el.Id)
+		if !ok || !filter.includes(other) {
+			continue
+		}
+
+		if hit, ok := sweep(probe, start, direction, maxDistance, other); ok && (!found || hit.Distance < closest.Distance) {
+			closest, found = hit, true
+		}
+	}
+
+	return closest, found
+}
+
+// Sweeps the probe from start along the direction until it touches the other collider within maxDistance.
+// A box swept at a box, where a ray is a box without a size, is tested exactly, and other shapes by conservative advancement.
+func sweep(probe *Collider, start vec, direction vec, maxDistance float32, other *Collider) (Hit, bool) {
+	if other.shape == nil && (probe.shape == nil || probe.shape == Circle{}) {
+		return sweepBox(probe.rectAt(start), direction, maxDistance, other)
+	}
+
+	return advance(probe, start, direction, maxDistance, other)
+}
+
+// Sweeps the rect along the direction with the slab test of the tilemap, which is exact however shallow the angle
+func sweepBox(rect quadtree.Rect, direction vec, maxDistance float32, other *Collider) (Hit, bool) {
+	distance, normal, ok := sweepRects(rect, direction, other.Rect)
+	if !ok || distance > maxDistance {
+		return Hit{}, false
+	}
+
+	if distance > 0 {
+		rect.X, rect.Y = rect.X+direction.X*distance, rect.Y+direction.Y*distance
+	}
+
+	// the middle of where the box and the other rect meet, which is the point a ray is at
+	overlap := vec{X: max(rect.X, other.Rect.X), Y: max(rect.Y, other.Rect.Y)}
+	point := overlap.Midpoint(vec{X: min(rect.Right(), other.Rect.Right()), Y: min(rect.Bottom(), other.Rect.Bottom())})
+	if distance > 0 {
+		// on the face that was hit
+		if normal.X != 0 {
+			point.X = other.Rect.X
+			if normal.X > 0 {
+				point.X = other.Rect.Right()
+			}
+		} else {
+			point.Y = other.Rect.Y
+			if normal.Y > 0 {
+				point.Y = other.Rect.Bottom()
+			}
+		}
+	}
+
+	return Hit{Collider: other, Distance: distance, Point: point, Normal: normal}, true
+}
+
+// Conservative advancement, the probe is repeatedly moved forward until the two touch or the probe passes maxDistance.
+// The normal of the contact separates the shapes by the gap between them, so the probe is moved by as far as it has to go
+// along the direction to close that gap, which reaches a flat face in one step however shallow the angle.
+func advance(probe *Collider, start vec, direction vec, maxDistance float32, other *Collider) (Hit, bool) {
+	var distance float32
+
+	for distance <= maxDistance {
+		probe.Pos = start.Add(direction.Multiply(distance))
+		probe.Rect = probe.rectAt(probe.Pos)
+
+		manifold := probe.ContactWith(other)
+		if manifold.Depth < -castTolerance {
+			closing := direction.Dot(manifold.Normal)
+			if closing <= 0 {
+				// moving away from or along the gap, which can never close
+				return Hit{}, false
+			}
+
+			distance -= manifold.Depth / closing
+			continue
+		}
+
+		overlapping := distance == 0 && manifold.Depth > castTolerance
+		if !overlapping && direction.Dot(manifold.Normal) <= 0 {
+			// the probe only grazes the collider, and convex shapes cannot be hit after being grazed
+			return Hit{}, false
+		}
+
+		point := manifold.Points[0]
+		if manifold.PointCount == 2 {
+			point = point.Midpoint(manifold.Points[1])
+		}
+
+		return Hit{Collider: other, Distance: distance, Point: point, Normal: manifold.Normal.Multiply(-1)}, true
+	}
+
+	return Hit{}, false
+}
@@ -0,0 +1,213 @@
+package collision
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/TheRaizer/GolangGame/core"
+	"github.com/TheRaizer/GolangGame/util"
+	"github.com/TheRaizer/GolangGame/util/datastructures/quadtree"
+	"github.com/stretchr/testify/require"
+)
+
+type queryScene struct {
+	collisionSys *CollisionSystem
+	wall         *Collider
+	enemy        *Collider
+	sensor       *Collider
+	floor        *Collider
+	ground       *Collider
+}
+
+// a tall wall, an enemy circle in front of it and a trigger sensor below the enemy,
+// with a long floor far below them and a long polygon ground below that
+func newQueryScene() queryScene {
+	collisionSys := NewCollisionSystem(quadtree.Rect{X: 0, Y: 0, W: 200, H: 200})
+	return queryScene{
+		collisionSys: &collisionSys,
+		wall:         NewCollider(core.WALL_LAYER, "wall", quadtree.Rect{X: 50, Y: 0, W: 10, H: 100}, &collisionSys, &collisionSys, nil, nil),
+		enemy:        NewShapeCollider(core.ENEMY_LAYER, "enemy", vec{X: 30, Y: 50}, Circle{vec{}, 5}, &collisionSys, &collisionSys, nil, nil),
+		sensor: func() *Collider {
+			sensor := NewCollider(core.WALL_LAYER, "sensor", quadtree.Rect{X: 20, Y: 80, W: 10, H: 10}, &collisionSys, &collisionSys, nil, nil)
+			sensor.SetTrigger(true)
+			return sensor
+		}(),
+		floor: NewCollider(core.WALL_LAYER, "floor", quadtree.Rect{X: -500, Y: 300, W: 1000, H: 10}, &collisionSys, &collisionSys, nil, nil),
+		ground: NewShapeCollider(
+			core.WALL_LAYER,
+			"ground",
+			vec{X: -500, Y: 400},
+			NewPolygon(vec{X: 0, Y: 0}, vec{X: 1000, Y: 0}, vec{X: 1000, Y: 10}, vec{X: 0, Y: 10}),
+			&collisionSys,
+			&collisionSys,
+			nil,
+			nil,
+		),
+	}
+}
+
+// a cast from 10 above the top of the collider at x -400, rising by slope for every pixel it moves right,
+// which is a shallow angle that reaches the collider far along the cast
+func shallowCast(top float32, slope float32) (vec, vec, float32) {
+	reach := 10 / slope
+	return vec{X: -400, Y: top - 10}, vec{X: 1, Y: slope}, reach * float32(math.Sqrt(float64(1+slope*slope)))
+}
+
+func TestRaycast(t *testing.T) {
+	type TestInput struct {
+		origin      vec
+		direction   vec
+		maxDistance float32
+		filter      QueryFilter
+	}
+
+	type TestExpected struct {
+		id       string // empty when nothing is hit
+		distance float32
+		point    vec
+		normal   vec
+	}
+
+	const NAME string = "should hit %+v"
+	getName := func(input TestInput) string {
+		return fmt.Sprintf(NAME, input)
+	}
+
+	all := QueryFilter{Mask: ALL_LAYERS}
+	right := vec{X: 1, Y: 0}
+
+	cases := []util.TestCase[TestInput, TestExpected]{
+		{Name: getName, Input: TestInput{vec{X: 0, Y: 5}, right, 100, all}, Expected: TestExpected{"wall", 50, vec{X: 50, Y: 5}, vec{X: -1, Y: 0}}},
+		{Name: getName, Input: TestInput{vec{X: 0, Y: 50}, right, 100, all}, Expected: TestExpected{"enemy", 25, vec{X: 25, Y: 50}, vec{X: -1, Y: 0}}},
+		{Name: getName, Input: TestInput{vec{X: 100, Y: 50}, vec{X: -1, Y: 0}, 100, all}, Expected: TestExpected{"wall", 40, vec{X: 60, Y: 50}, vec{X: 1, Y: 0}}},
+		// the direction does not need to be normalized
+		{Name: getName, Input: TestInput{vec{X: 30, Y: 0}, vec{X: 0, Y: 10}, 100, all}, Expected: TestExpected{"enemy", 45, vec{X: 30, Y: 45}, vec{X: 0, Y: -1}}},
+		// filtered out by layer, by the ignore list and as a trigger
+		{
+			Name:     getName,
+			Input:    TestInput{vec{X: 0, Y: 50}, right, 100, QueryFilter{Mask: LayerMask(core.WALL_LAYER)}},
+			Expected: TestExpected{"wall", 50, vec{X: 50, Y: 50}, vec{X: -1, Y: 0}},
+		},
+		{
+			Name: getName,
+			// nil is replaced with the enemy of the scene
+			Input:    TestInput{vec{X: 0, Y: 50}, right, 100, QueryFilter{Mask: ALL_LAYERS, Ignore: []*Collider{nil}}},
+			Expected: TestExpected{"wall", 50, vec{X: 50, Y: 50}, vec{X: -1, Y: 0}},
+		},
+		{Name: getName, Input: TestInput{vec{X: 0, Y: 85}, right, 100, all}, Expected: TestExpected{"sensor", 20, vec{X: 20, Y: 85}, vec{X: -1, Y: 0}}},
+		// the zero filter includes no layers
+		{Name: getName, Input: TestInput{vec{X: 0, Y: 50}, right, 100, QueryFilter{}}, Expected: TestExpected{}},
+		{
+			Name:     getName,
+			Input:    TestInput{vec{X: 0, Y: 85}, right, 100, QueryFilter{Mask: ALL_LAYERS, IgnoreTriggers: true}},
+			Expected: TestExpected{"wall", 50, vec{X: 50, Y: 85}, vec{X: -1, Y: 0}},
+		},
+		// missing by being too short, pointing away or passing by
+		{Name: getName, Input: TestInput{vec{X: 0, Y: 5}, right, 40, all}, Expected: TestExpected{}},
+		{Name: getName, Input: TestInput{vec{X: 0, Y: 5}, vec{X: -1, Y: 0}, 100, all}, Expected: TestExpected{}},
+		{Name: getName, Input: TestInput{vec{X: 0, Y: 150}, right, 100, all}, Expected: TestExpected{}},
+		// starting inside a collider hits it straight away, on the side the origin is closest to
+		{Name: getName, Input: TestInput{vec{X: 52, Y: 50}, right, 100, all}, Expected: TestExpected{"wall", 0, vec{X: 52, Y: 50}, vec{X: -1, Y: 0}}},
+	}
+
+	// reaching a rect and a polygon at shallow angles
+	for _, slope := range []float32{0.3, 0.1, 0.05, 0.02} {
+		for _, id := range []string{"floor", "ground"} {
+			top := map[string]float32{"floor": 300, "ground": 400}[id]
+			origin, direction, distance := shallowCast(top, slope)
+			cases = append(cases, util.TestCase[TestInput, TestExpected]{
+				Name:     getName,
+				Input:    TestInput{origin, direction, 2000, all},
+				Expected: TestExpected{id, distance, vec{X: origin.X + 10/slope, Y: top}, vec{X: 0, Y: -1}},
+			})
+		}
+	}
+
+	util.IterateTestCases(cases, t, func(testCase util.TestCase[TestInput, TestExpected]) {
+		scene := newQueryScene()
+		input := testCase.Input
+		for i, ignored := range input.filter.Ignore {
+			if ignored == nil {
+				input.filter.Ignore[i] = scene.enemy
+			}
+		}
+
+		hit, ok := scene.collisionSys.Raycast(input.origin, input.direction, input.maxDistance, input.filter)
+		if testCase.Expected.id == "" {
+			require.False(t, ok)
+			return
+		}
+
+		require.True(t, ok)
+		require.Equal(t, testCase.Expected.id, hit.Collider.ID())
+		require.InDelta(t, testCase.Expected.distance, hit.Distance, castTolerance)
+		require.InDelta(t, testCase.Expected.point.X, hit.Point.X, castTolerance)
+		require.InDelta(t, testCase.Expected.point.Y, hit.Point.Y, castTolerance)
+		require.Equal(t, testCase.Expected.normal, hit.Normal)
+	})
+}
+
+func TestBoxCast(t *testing.T) {
+	scene := newQueryScene()
+	all := QueryFilter{Mask: ALL_LAYERS}
+
+	hit, ok := scene.collisionSys.BoxCast(quadtree.Rect{X: 0, Y: 40, W: 10, H: 10}, vec{X: 1, Y: 0}, 100, all)
+	require.True(t, ok)
+	require.Same(t, scene.enemy, hit.Collider)
+	require.Equal(t, float32(15), hit.Distance)
+	require.Equal(t, vec{X: 25, Y: 50}, hit.Point)
+	require.Equal(t, vec{X: -1, Y: 0}, hit.Normal)
+
+	// moving diagonally in to the wall until the right side of the box reaches it
+	hit, ok = scene.collisionSys.BoxCast(quadtree.Rect{X: 0, Y: 0, W: 10, H: 10}, vec{X: 1, Y: 1}, 100, all)
+	require.True(t, ok)
+	require.Same(t, scene.wall, hit.Collider)
+	require.InDelta(t, 40*math.Sqrt2, hit.Distance, castTolerance*2)
+	require.Equal(t, vec{X: -1, Y: 0}, hit.Normal)
+
+	// sliding along the top of the sensor does not hit it
+	_, ok = scene.collisionSys.BoxCast(quadtree.Rect{X: 0, Y: 70, W: 10, H: 10}, vec{X: 1, Y: 0}, 30, all)
+	require.False(t, ok)
+
+	// reaching a rect and a polygon at shallow angles, with the bottom of the box where a ray would start
+	for _, slope := range []float32{0.3, 0.1, 0.05, 0.02} {
+		for _, collider := range []*Collider{scene.floor, scene.ground} {
+			origin, direction, distance := shallowCast(collider.Rect.Y, slope)
+
+			hit, ok = scene.collisionSys.BoxCast(quadtree.Rect{X: origin.X, Y: origin.Y - 10, W: 10, H: 10}, direction, 2000, all)
+			require.True(t, ok)
+			require.Same(t, collider, hit.Collider)
+			require.InDelta(t, distance, hit.Distance, castTolerance)
+			require.InDelta(t, collider.Rect.Y, hit.Point.Y, castTolerance)
+			require.Equal(t, vec{X: 0, Y: -1}, hit.Normal)
+		}
+	}
+}
+
+func TestOverlapQueries(t *testing.T) {
+	scene := newQueryScene()
+	all := QueryFilter{Mask: ALL_LAYERS}
+
+	require.Equal(t, []*Collider{scene.wall}, scene.collisionSys.OverlapBox(quadtree.Rect{X: 45, Y: 45, W: 10, H: 10}, all, nil))
+	require.ElementsMatch(t, []*Collider{scene.wall, scene.enemy}, scene.collisionSys.OverlapCircle(vec{X: 30, Y: 50}, 21, all, nil))
+	// the bounding box of the circle reaches the wall but the circle does not
+	require.Equal(t, []*Collider{scene.enemy}, scene.collisionSys.OverlapCircle(vec{X: 30, Y: 50}, 19, all, nil))
+
+	require.Empty(t, scene.collisionSys.OverlapCircle(vec{X: 30, Y: 50}, 21, QueryFilter{}, nil))
+	require.Empty(t, scene.collisionSys.OverlapCircle(vec{X: 30, Y: 50}, 21, QueryFilter{Mask: LayerMask()}, nil))
+
+	enemies := QueryFilter{Mask: LayerMask(core.ENEMY_LAYER)}
+	require.Equal(t, []*Collider{scene.enemy}, scene.collisionSys.OverlapCircle(vec{X: 30, Y: 50}, 21, enemies, nil))
+
+	scene.enemy.Disable()
+	require.Empty(t, scene.collisionSys.OverlapCircle(vec{X: 30, Y: 50}, 19, all, nil))
+}
+
+func TestCastShouldPanicWithoutDirection(t *testing.T) {
+	scene := newQueryScene()
+
+	require.PanicsWithValue(t, "cannot cast without a direction", func() {
+		scene.collisionSys.Raycast(vec{}, vec{}, 10, QueryFilter{Mask: ALL_LAYERS})
+	})
+}
@@ -0,0 +1,182 @@
+package collision
+
+import (
+	"math"
+
+	"github.com/TheRaizer/GolangGame/util"
+	"github.com/TheRaizer/GolangGame/util/datastructures/quadtree"
+)
+
+// The area a collider covers when it is not an axis-aligned rect.
+// Shapes are positioned relative to the position of their collider, and the quadtree indexes their bounding box.
+type Shape interface {
+	// Returns the bounding box of the shape when its collider is at pos
+	Bounds(pos util.Vec2[float32]) quadtree.Rect
+}
+
+type Circle struct {
+	Center util.Vec2[float32]
+	Radius float32
+}
+
+// A convex polygon
+type Polygon struct {
+	Points []util.Vec2[float32]
+}
+
+// The area within Radius of the segment from A to B
+type Capsule struct {
+	A, B   util.Vec2[float32]
+	Radius float32
+}
+
+// Steepness of slopes, in radians
+const (
+	SLOPE_22_5 = math.Pi / 8
+	SLOPE_45   = math.Pi / 4
+)
+
+// Creates a convex polygon from points given in either winding order
+func NewPolygon(points ...util.Vec2[float32]) Polygon {
+	if len(points) < 3 {
+		panic("a polygon needs at least 3 points")
+	}
+
+	// a repeated point leaves an edge without a direction to take the normal of
+	if hasDuplicatePoints(points) {
+		panic("the points of a polygon must be distinct")
+	}
+
+	if !isConvex(points) {
+		panic("polygon must be convex")
+	}
+
+	return Polygon{points}
+}
+
+// Creates a right triangle for a ramp of the given width that rises by angle radians towards the right when risesRight,
+// or towards the left otherwise. The points are relative to the top left of the bounding box of the ramp.
+func NewSlope(width float32, angle float64, risesRight bool) Polygon {
+	if angle <= 0 || angle >= math.Pi/2 {
+		panic("a slope must be steeper than flat and less steep than vertical")
+	}
+
+	height := width * float32(math.Tan(angle))
+	if risesRight {
+		return NewPolygon(util.Vec2[float32]{X: 0, Y: height}, util.Vec2[float32]{X: width, Y: height}, util.Vec2[float32]{X: width, Y: 0})
+	}
+
+	return NewPolygon(util.Vec2[float32]{X: 0, Y: 0}, util.Vec2[float32]{X: 0, Y: height}, util.Vec2[float32]{X: width, Y: height})
+}
+
+func (circle Circle) Bounds(pos util.Vec2[float32]) quadtree.Rect {
+	return quadtree.Rect{
+		X: pos.X + circle.Center.X - circle.Radius,
+		Y: pos.Y + circle.Center.Y - circle.Radius,
+		W: circle.Radius * 2,
+		H: circle.Radius * 2,
+	}
+}
+
+func (polygon Polygon) Bounds(pos util.Vec2[float32]) quadtree.Rect {
+	minX, minY := polygon.Points[0].X, polygon.Points[0].Y
+	maxX, maxY := minX, minY
+
+	for _, point := range polygon.Points[1:] {
+		minX, maxX = min(minX, point.X), max(maxX, point.X)
+		minY, maxY = min(minY, point.Y), max(maxY, point.Y)
+	}
+
+	return quadtree.Rect{X: pos.X + minX, Y: pos.Y + minY, W: maxX - minX, H: maxY - minY}
+}
+
+func (capsule Capsule) Bounds(pos util.Vec2[float32]) quadtree.Rect {
+	minX, maxX := min(capsule.A.X, capsule.B.X), max(capsule.A.X, capsule.B.X)
+	minY, maxY := min(capsule.A.Y, capsule.B.Y), max(capsule.A.Y, capsule.B.Y)
+
+	return quadtree.Rect{
+		X: pos.X + minX - capsule.Radius,
+		Y: pos.Y + minY - capsule.Radius,
+		W: maxX - minX + capsule.Radius*2,
+		H: maxY - minY + capsule.Radius*2,
+	}
+}
+
+// Returns a copy of the polygon moved by offset
+func (polygon Polygon) Offset(offset util.Vec2[float32]) Polygon {
+	points := make([]util.Vec2[float32], len(polygon.Points))
+	for i, point := range polygon.Points {
+		points[i] = point.Add(offset)
+	}
+
+	return Polygon{points}
+}
+
+// Returns a copy of the polygon rotated by angle radians about pivot, for rotating hazards
+func (polygon Polygon) Rotated(angle float32, pivot util.Vec2[float32]) Polygon {
+	points := make([]util.Vec2[float32], len(polygon.Points))
+	for i, point := range polygon.Points {
+		points[i] = rotate(point, angle, pivot)
+	}
+
+	return Polygon{points}
+}
+
+// Returns the capsule rotated by angle radians about pivot
+func (capsule Capsule) Rotated(angle float32, pivot util.Vec2[float32]) Capsule {
+	return Capsule{rotate(capsule.A, angle, pivot), rotate(capsule.B, angle, pivot), capsule.Radius}
+}
+
+func rotate(point util.Vec2[float32], angle float32, pivot util.Vec2[float32]) util.Vec2[float32] {
+	sin, cos := math.Sincos(float64(angle))
+	offset := point.Sub(pivot)
+
+	return util.Vec2[float32]{
+		X: pivot.X + offset.X*float32(cos) - offset.Y*float32(sin),
+		Y: pivot.Y + offset.X*float32(sin) + offset.Y*float32(cos),
+	}
+}
+
+func hasDuplicatePoints(points []util.Vec2[float32]) bool {
+	for i, point := range points {
+		for _, other := range points[i+1:] {
+			if point == other {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// whether every turn along the points is in the same direction, and they wind around only once,
+// as points that turn the same way can still cross over themselves like the points of a star
+func isConvex(points []util.Vec2[float32]) bool {
+	var sign float32
+	var winding float64
+	for i := range points {
+		a, b, c := points[i], points[(i+1)%len(points)], points[(i+2)%len(points)]
+
+		edge, next := b.Sub(a), c.Sub(b)
+		turn := edge.Cross(next)
+		winding += math.Atan2(float64(turn), float64(edge.Dot(next)))
+
+		if turn == 0 {
+			// the points double back along the same line
+			if edge.Dot(next) < 0 {
+				return false
+			}
+
+			continue
+		}
+
+		if sign == 0 {
+			sign = turn
+		} else if (turn > 0) != (sign > 0) {
+			return false
+		}
+	}
+
+	// every point lies on a single line
+	return sign != 0 && math.Abs(winding) < 3*math.Pi
+}
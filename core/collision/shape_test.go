@@ -0,0 +1,131 @@
+package collision
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/TheRaizer/GolangGame/util"
+	"github.com/TheRaizer/GolangGame/util/datastructures/quadtree"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBounds(t *testing.T) {
+	const NAME string = "should return the bounding box of %+v"
+	getName := func(input Shape) string {
+		return fmt.Sprintf(NAME, input)
+	}
+
+	pos := util.Vec2[float32]{X: 100, Y: 50}
+	cases := []util.TestCase[Shape, quadtree.Rect]{
+		{Name: getName, Input: Circle{vec{X: 0, Y: 0}, 10}, Expected: quadtree.Rect{X: 90, Y: 40, W: 20, H: 20}},
+		{Name: getName, Input: Circle{vec{X: 5, Y: -5}, 2.5}, Expected: quadtree.Rect{X: 102.5, Y: 42.5, W: 5, H: 5}},
+		{
+			Name:     getName,
+			Input:    NewPolygon(vec{X: 0, Y: 10}, vec{X: 20, Y: 0}, vec{X: 15, Y: 30}),
+			Expected: quadtree.Rect{X: 100, Y: 50, W: 20, H: 30},
+		},
+		{Name: getName, Input: Capsule{vec{X: 0, Y: 0}, vec{X: 0, Y: 20}, 4}, Expected: quadtree.Rect{X: 96, Y: 46, W: 8, H: 28}},
+		{Name: getName, Input: Capsule{vec{X: 10, Y: 0}, vec{X: 0, Y: 5}, 1}, Expected: quadtree.Rect{X: 99, Y: 49, W: 12, H: 7}},
+	}
+
+	util.IterateTestCases(cases, t, func(testCase util.TestCase[Shape, quadtree.Rect]) {
+		require.Equal(t, testCase.Expected, testCase.Input.Bounds(pos))
+	})
+}
+
+func TestNewPolygonShouldPanic(t *testing.T) {
+	const NAME string = "should panic when given the points %v"
+	getName := func(input []vec) string {
+		return fmt.Sprintf(NAME, input)
+	}
+
+	cases := []util.TestCase[[]vec, string]{
+		{Name: getName, Input: []vec{{X: 0, Y: 0}, {X: 1, Y: 1}}, Expected: "a polygon needs at least 3 points"},
+		{Name: getName, Input: []vec{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 2, Y: 2}, {X: 0, Y: 10}}, Expected: "polygon must be convex"},
+		{
+			Name:     getName,
+			Input:    []vec{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 0}, {X: 0, Y: 10}},
+			Expected: "the points of a polygon must be distinct",
+		},
+		{
+			Name:     getName,
+			Input:    []vec{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10}, {X: 0, Y: 0}},
+			Expected: "the points of a polygon must be distinct",
+		},
+		// the points of a star turn the same way but cross over themselves
+		{
+			Name:     getName,
+			Input:    []vec{{X: 0, Y: -10}, {X: 6, Y: 8}, {X: -9, Y: -3}, {X: 9, Y: -3}, {X: -6, Y: 8}},
+			Expected: "polygon must be convex",
+		},
+		{Name: getName, Input: []vec{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 5, Y: 0}, {X: 5, Y: 10}}, Expected: "polygon must be convex"},
+	}
+
+	util.IterateTestCases(cases, t, func(testCase util.TestCase[[]vec, string]) {
+		require.PanicsWithValue(t, testCase.Expected, func() {
+			NewPolygon(testCase.Input...)
+		})
+	})
+
+	// both winding orders are convex
+	require.NotPanics(t, func() {
+		NewPolygon(vec{X: 0, Y: 0}, vec{X: 10, Y: 0}, vec{X: 10, Y: 10}, vec{X: 0, Y: 10})
+		NewPolygon(vec{X: 0, Y: 10}, vec{X: 10, Y: 10}, vec{X: 10, Y: 0}, vec{X: 0, Y: 0})
+		NewPolygon(vec{X: 0, Y: 0}, vec{X: 5, Y: 0}, vec{X: 10, Y: 0}, vec{X: 10, Y: 10})
+	})
+}
+
+func TestNewSlope(t *testing.T) {
+	type TestInput struct {
+		angle      float64
+		risesRight bool
+	}
+
+	const NAME string = "should create the slope %+v"
+	getName := func(input TestInput) string {
+		return fmt.Sprintf(NAME, input)
+	}
+
+	rise := float32(20 * math.Tan(SLOPE_22_5))
+	cases := []util.TestCase[TestInput, []vec]{
+		{Name: getName, Input: TestInput{SLOPE_45, true}, Expected: []vec{{X: 0, Y: 20}, {X: 20, Y: 20}, {X: 20, Y: 0}}},
+		{Name: getName, Input: TestInput{SLOPE_45, false}, Expected: []vec{{X: 0, Y: 0}, {X: 0, Y: 20}, {X: 20, Y: 20}}},
+		{Name: getName, Input: TestInput{SLOPE_22_5, true}, Expected: []vec{{X: 0, Y: rise}, {X: 20, Y: rise}, {X: 20, Y: 0}}},
+	}
+
+	util.IterateTestCases(cases, t, func(testCase util.TestCase[TestInput, []vec]) {
+		slope := NewSlope(20, testCase.Input.angle, testCase.Input.risesRight)
+
+		require.Len(t, slope.Points, len(testCase.Expected))
+		for i, point := range testCase.Expected {
+			require.InDelta(t, point.X, slope.Points[i].X, 1e-4)
+			require.InDelta(t, point.Y, slope.Points[i].Y, 1e-4)
+		}
+	})
+
+	for _, angle := range []float64{0, math.Pi / 2} {
+		require.PanicsWithValue(t, "a slope must be steeper than flat and less steep than vertical", func() {
+			NewSlope(20, angle, true)
+		})
+	}
+}
+
+func TestRotated(t *testing.T) {
+	square := NewPolygon(vec{X: 0, Y: 0}, vec{X: 10, Y: 0}, vec{X: 10, Y: 10}, vec{X: 0, Y: 10})
+	rotated := square.Rotated(math.Pi/2, vec{X: 5, Y: 5})
+
+	expected := []vec{{X: 10, Y: 0}, {X: 10, Y: 10}, {X: 0, Y: 10}, {X: 0, Y: 0}}
+	for i, point := range rotated.Points {
+		require.InDelta(t, expected[i].X, point.X, 0.0001)
+		require.InDelta(t, expected[i].Y, point.Y, 0.0001)
+	}
+
+	// the original is left as it was
+	require.Equal(t, vec{X: 0, Y: 0}, square.Points[0])
+
+	capsule := Capsule{vec{X: 0, Y: 0}, vec{X: 10, Y: 0}, 2}.Rotated(math.Pi/2, vec{X: 0, Y: 0})
+	require.InDelta(t, 0, capsule.B.X, 0.0001)
+	require.InDelta(t, 10, capsule.B.Y, 0.0001)
+	require.Equal(t, float32(2), capsule.Radius)
+}
@@ -0,0 +1,66 @@
+package collision
+
+import "github.com/TheRaizer/GolangGame/util/datastructures/quadtree"
+
+// The system for the colliders of level geometry that never moves, such as wall tiles.
+// Static colliders are only collected when registered, and are bulk loaded in to their own quadtree by
+// CollisionSystem.BuildStaticLayer once the level is loaded, so that only moving colliders pay for incremental updates.
+// Static colliders are collided with but never look for collisions themselves, so their collision events are not called.
+type StaticLayer struct {
+	collisionSys *CollisionSystem
+}
+
+func (layer StaticLayer) RegisterObject(collider *Collider) {
+	layer.collisionSys.staticColliders[collider.ID()] = collider
+}
+
+func (layer StaticLayer) DeregisterObject(collider *Collider) {
+	delete(layer.collisionSys.staticColliders, collider.ID())
+
+	// colliders registered since the last build are not in the static tree yet
+	if layer.collisionSys.staticBuilt[collider.ID()] {
+		delete(layer.collisionSys.staticBuilt, collider.ID())
+		layer.collisionSys.static.Remove(quadtree.QuadElement{Rect: collider.Rect, Id: collider.ID()})
+	}
+}
+
+func (layer StaticLayer) OnLoop() {}
+
+// Combines the static and dynamic trees so that queries see the colliders of both,
+// while inserts, removals and moves only ever reach the dynamic tree
+type layeredTree struct {
+	quadtree.QuadTree // the dynamic tree
+	static            *quadtree.BaseQuadTree
+}
+
+func (tree layeredTree) Query(hitbox quadtree.Rect) []quadtree.QuadElement {
+	if tree.static == nil {
+		return tree.QuadTree.Query(hitbox)
+	}
+
+	return tree.QueryAppend(hitbox, nil, nil)
+}
+
+func (tree layeredTree) QueryAppend(hitbox quadtree.Rect, filter quadtree.Filter, buf []quadtree.QuadElement) []quadtree.QuadElement {
+	if tree.static != nil {
+		buf = tree.static.QueryAppend(hitbox, filter, buf)
+	}
+
+	return tree.QuadTree.QueryAppend(hitbox, filter, buf)
+}
+
+func (tree layeredTree) VisitQuery(hitbox quadtree.Rect, filter quadtree.Filter, visitor quadtree.Visitor) {
+	if tree.static != nil {
+		stopped := false
+		tree.static.VisitQuery(hitbox, filter, func(el quadtree.QuadElement) bool {
+			stopped = !visitor(el)
+			return !stopped
+		})
+
+		if stopped {
+			return
+		}
+	}
+
+	tree.QuadTree.VisitQuery(hitbox, filter, visitor)
+}
@@ -0,0 +1,142 @@
+package collision
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/TheRaizer/GolangGame/core"
+	"github.com/TheRaizer/GolangGame/util"
+	"github.com/TheRaizer/GolangGame/util/datastructures/quadtree"
+	"github.com/stretchr/testify/require"
+)
+
+// a floor of static tiles with a player standing on it
+func newStaticScene(collisionSys *CollisionSystem, onWallCollision func(els []quadtree.QuadElement)) (*Collider, []*Collider) {
+	tiles := make([]*Collider, 0)
+	for i := 0; i < 20; i++ {
+		tiles = append(tiles, NewCollider(
+			core.WALL_LAYER,
+			fmt.Sprintf("tile%d", i),
+			quadtree.Rect{X: float32(i) * 32, Y: 500, W: 32, H: 32},
+			collisionSys.StaticLayer(),
+			collisionSys,
+			[]func(els []quadtree.QuadElement){onWallCollision},
+			nil,
+		))
+	}
+
+	player := NewCollider(core.PLAYER_LAYER, "player", quadtree.Rect{X: 40, Y: 470, W: 32, H: 32}, collisionSys, collisionSys, nil, nil)
+	return player, tiles
+}
+
+func TestStaticLayerShouldOnlyBeCollidedWithOnceBuilt(t *testing.T) {
+	collisionSys := NewCollisionSystem(quadtree.Rect{X: 0, Y: 0, W: 800, H: 600})
+	player, _ := newStaticScene(&collisionSys, nil)
+
+	require.Equal(t, []string{"player"}, ids(collisionSys.DetectCollisions(player.Rect)))
+
+	collisionSys.BuildStaticLayer()
+
+	require.ElementsMatch(t, []string{"player", "tile1", "tile2"}, ids(collisionSys.DetectCollisions(player.Rect)))
+	require.ElementsMatch(
+		t,
+		[]string{"tile1", "tile2"},
+		ids(collisionSys.DetectCollisionsAppend(player.Rect, collisionSys.LayerFilter(LayerMask(core.WALL_LAYER)), nil)),
+	)
+}
+
+func TestStaticLayerShouldNotBeInTheDynamicTree(t *testing.T) {
+	collisionSys := NewCollisionSystem(quadtree.Rect{X: 0, Y: 0, W: 800, H: 600})
+	newStaticScene(&collisionSys, nil)
+	collisionSys.BuildStaticLayer()
+
+	require.Equal(t, []string{"player"}, ids(collisionSys.tree.Query(quadtree.Rect{X: 0, Y: 0, W: 800, H: 600})))
+	require.NoError(t, collisionSys.static.Validate())
+}
+
+func TestOnLoopShouldCollideWithStaticLayer(t *testing.T) {
+	const NAME string = "should collide dynamic colliders with the static layer using %d workers"
+	getName := func(input int) string {
+		return fmt.Sprintf(NAME, input)
+	}
+
+	cases := []util.TestCase[int, []string]{
+		{Name: getName, Input: 1, Expected: []string{"tile1", "tile2"}},
+		{Name: getName, Input: 4, Expected: []string{"tile1", "tile2"}},
+	}
+
+	util.IterateTestCases(cases, t, func(testCase util.TestCase[int, []string]) {
+		collisionSys := NewCollisionSystem(quadtree.Rect{X: 0, Y: 0, W: 800, H: 600})
+		player, _ := newStaticScene(&collisionSys, func(els []quadtree.QuadElement) {
+			require.Fail(t, "static colliders should not look for collisions")
+		})
+
+		var collided []string
+		player.AddCollisionEvent(func(els []quadtree.QuadElement) {
+			collided = ids(els)
+		})
+
+		collisionSys.BuildStaticLayer()
+		collisionSys.SetWorkers(testCase.Input)
+		collisionSys.OnLoop()
+
+		require.ElementsMatch(t, testCase.Expected, collided)
+	})
+}
+
+func TestStaticLayerDeregisterShouldRemoveFromStaticTree(t *testing.T) {
+	collisionSys := NewCollisionSystem(quadtree.Rect{X: 0, Y: 0, W: 800, H: 600})
+	player, tiles := newStaticScene(&collisionSys, nil)
+	collisionSys.BuildStaticLayer()
+
+	collisionSys.StaticLayer().DeregisterObject(tiles[1])
+
+	require.ElementsMatch(t, []string{"player", "tile2"}, ids(collisionSys.DetectCollisions(player.Rect)))
+
+	// rebuilding should not bring the tile back
+	collisionSys.BuildStaticLayer()
+	require.ElementsMatch(t, []string{"player", "tile2"}, ids(collisionSys.DetectCollisions(player.Rect)))
+}
+
+func TestStaticLayerDeregisterShouldIgnoreCollidersRegisteredAfterTheBuild(t *testing.T) {
+	collisionSys := NewCollisionSystem(quadtree.Rect{X: 0, Y: 0, W: 800, H: 600})
+	player, _ := newStaticScene(&collisionSys, nil)
+	collisionSys.BuildStaticLayer()
+
+	late := NewCollider(
+		core.WALL_LAYER,
+		"late",
+		quadtree.Rect{X: 320, Y: 500, W: 32, H: 32}, // within the bounds of the built tree
+		collisionSys.StaticLayer(),
+		&collisionSys,
+		nil,
+		nil,
+	)
+
+	require.NotPanics(t, func() {
+		collisionSys.StaticLayer().DeregisterObject(late)
+	})
+	require.ElementsMatch(t, []string{"player", "tile1", "tile2"}, ids(collisionSys.DetectCollisions(player.Rect)))
+
+	collisionSys.BuildStaticLayer()
+	require.ElementsMatch(t, []string{"player", "tile1", "tile2"}, ids(collisionSys.DetectCollisions(player.Rect)))
+}
+
+func TestMovingStaticColliderShouldPanic(t *testing.T) {
+	collisionSys := NewCollisionSystem(quadtree.Rect{X: 0, Y: 0, W: 800, H: 600})
+	_, tiles := newStaticScene(&collisionSys, nil)
+	collisionSys.BuildStaticLayer()
+
+	require.PanicsWithValue(t, "static colliders cannot be moved: tile0", func() {
+		tiles[0].UpdatePos(1, 0)
+	})
+}
+
+func ids(els []quadtree.QuadElement) []string {
+	ids := make([]string, 0, len(els))
+	for _, el := range els {
+		ids = append(ids, el.Id)
+	}
+
+	return ids
+}
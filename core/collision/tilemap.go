@@ -0,0 +1,378 @@
+package collision
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/TheRaizer/GolangGame/util"
+	"github.com/TheRaizer/GolangGame/util/datastructures/quadtree"
+)
+
+// The collision geometry of a tile based level, a grid of cells that are either solid or empty.
+// Rather than a collider per tile, adjacent solid tiles are merged in to as few rects as possible, which are
+// registered with the static layer of the collision system so that colliders and scene queries collide with them.
+// Merging also removes the edges between neighbouring tiles that moving colliders would otherwise catch on.
+// Tiles can also be slopes, which are never merged and have a collider each.
+// Overlap and sweep queries against the tiles alone are answered from the grid directly.
+type TileMap struct {
+	Origin   util.Vec2[float32] // the top left corner of the first tile
+	TileSize float32
+
+	name         string
+	layer        int
+	cols, rows   int
+	solid        []bool          // row major
+	slopes       map[int]Polygon // the shapes of the slope tiles by their index in solid, relative to their tile
+	colliders    []*Collider
+	collisionSys *CollisionSystem
+}
+
+// A solid tile found by a sweep through a tile map
+type TileHit struct {
+	Col, Row int
+	Distance float32            // how far the sweep travelled before touching the tile, 0 when it started overlapping it
+	Normal   util.Vec2[float32] // the side of the tile that was touched, or that is overlapped the least, pointing out of the tile
+}
+
+// Creates an empty tile map, whose merged rects are registered as static colliders on the given layer
+func NewTileMap(
+	layer int,
+	name string,
+	origin util.Vec2[float32],
+	tileSize float32,
+	cols int,
+	rows int,
+	collisionSys *CollisionSystem,
+) TileMap {
+	if tileSize <= 0 || cols <= 0 || rows <= 0 {
+		panic("a tile map needs a positive tile size and number of columns and rows")
+	}
+
+	return TileMap{
+		Origin:       origin,
+		TileSize:     tileSize,
+		name:         name,
+		layer:        layer,
+		cols:         cols,
+		rows:         rows,
+		solid:        make([]bool, cols*rows),
+		slopes:       make(map[int]Polygon),
+		collisionSys: collisionSys,
+	}
+}
+
+func (tileMap *TileMap) Size() (int, int) {
+	return tileMap.cols, tileMap.rows
+}
+
+// Marks a tile as solid or empty. Call Build afterwards for colliders to see the change.
+func (tileMap *TileMap) SetSolid(col int, row int, solid bool) {
+	if !tileMap.inBounds(col, row) {
+		panic(fmt.Sprintf("tile %d, %d is outside of the tile map", col, row))
+	}
+
+	tileMap.solid[row*tileMap.cols+col] = solid
+	delete(tileMap.slopes, row*tileMap.cols+col)
+}
+
+// Makes the tile a slope rising by angle radians towards the right when risesRight, or towards the left otherwise,
+// which sits on the bottom of the tile. Slopes less steep than SLOPE_45 do not reach the top of the tile.
+// Call Build afterwards for colliders to see the change.
+func (tileMap *TileMap) SetSlope(col int, row int, angle float64, risesRight bool) {
+	if angle > SLOPE_45 {
+		panic("slope tiles can be at most 45 degrees")
+	}
+
+	tileMap.SetSolid(col, row, false)
+
+	slope := NewSlope(tileMap.TileSize, angle, risesRight)
+	bounds := slope.Bounds(vec{})
+	tileMap.slopes[row*tileMap.cols+col] = slope.Offset(vec{X: 0, Y: tileMap.TileSize - bounds.H})
+}
+
+// Returns the shape of the tile, relative to the top left of the tile, and false when the tile is not a slope
+func (tileMap *TileMap) Slope(col int, row int) (Polygon, bool) {
+	if !tileMap.inBounds(col, row) {
+		return Polygon{}, false
+	}
+
+	slope, ok := tileMap.slopes[row*tileMap.cols+col]
+	return slope, ok
+}
+
+// an unregistered collider covering the slope tile, for testing against with the narrow phase
+func (tileMap *TileMap) slopeCollider(col int, row int) (Collider, bool) {
+	slope, ok := tileMap.Slope(col, row)
+	if !ok {
+		return Collider{}, false
+	}
+
+	rect := tileMap.TileRect(col, row)
+	pos := vec{X: rect.X, Y: rect.Y}
+	collider := Collider{Rect: slope.Bounds(pos), shape: slope}
+	collider.Pos = pos
+
+	return collider, true
+}
+
+// Returns whether the tile is solid, tiles outside of the tile map are empty
+func (tileMap *TileMap) IsSolid(col int, row int) bool {
+	return tileMap.inBounds(col, row) && tileMap.solid[row*tileMap.cols+col]
+}
+
+func (tileMap *TileMap) inBounds(col int, row int) bool {
+	return col >= 0 && row >= 0 && col < tileMap.cols && row < tileMap.rows
+}
+
+// Returns the rect covered by the tile
+func (tileMap *TileMap) TileRect(col int, row int) quadtree.Rect {
+	return quadtree.Rect{
+		X: tileMap.Origin.X + float32(col)*tileMap.TileSize,
+		Y: tileMap.Origin.Y + float32(row)*tileMap.TileSize,
+		W: tileMap.TileSize,
+		H: tileMap.TileSize,
+	}
+}
+
+// Returns the merged rects of the solid tiles followed by the slope tiles, as registered by the last Build
+func (tileMap *TileMap) Colliders() []*Collider {
+	return tileMap.colliders
+}
+
+// Merges the solid tiles in to rects, replaces the static colliders of the previous build with them and
+// a collider for each slope tile, then rebuilds the static layer of the collision system
+func (tileMap *TileMap) Build() {
+	staticLayer := tileMap.collisionSys.StaticLayer()
+	for _, collider := range tileMap.colliders {
+		staticLayer.DeregisterObject(collider)
+	}
+
+	// a new slice, so that the colliders returned for the previous build are left as they were
+	tileMap.colliders = make([]*Collider, 0, len(tileMap.colliders))
+	for i, rect := range tileMap.mergedRects() {
+		name := fmt.Sprintf("%s_%d", tileMap.name, i)
+		tileMap.colliders = append(tileMap.colliders, NewCollider(tileMap.layer, name, rect, staticLayer, tileMap.collisionSys, nil, nil))
+	}
+
+	for row := 0; row < tileMap.rows; row++ {
+		for col := 0; col < tileMap.cols; col++ {
+			slope, ok := tileMap.slopeCollider(col, row)
+			if !ok {
+				continue
+			}
+
+			name := fmt.Sprintf("%s_%d", tileMap.name, len(tileMap.colliders))
+			tileMap.colliders = append(
+				tileMap.colliders,
+				NewShapeCollider(tileMap.layer, name, slope.Pos, slope.shape, staticLayer, tileMap.collisionSys, nil, nil),
+			)
+		}
+	}
+
+	tileMap.collisionSys.BuildStaticLayer()
+}
+
+// Greedily merges the solid tiles, starting from the top left, in to the widest run of tiles along a row
+// that is not yet merged, which is then extended down for as many rows as the whole run is solid
+func (tileMap *TileMap) mergedRects() []quadtree.Rect {
+	rects := make([]quadtree.Rect, 0)
+	merged := make([]bool, len(tileMap.solid))
+	mergeable := func(col int, row int) bool {
+		return tileMap.IsSolid(col, row) && !merged[row*tileMap.cols+col]
+	}
+
+	for row := 0; row < tileMap.rows; row++ {
+		for col := 0; col < tileMap.cols; col++ {
+			if !mergeable(col, row) {
+				continue
+			}
+
+			width := 1
+			for mergeable(col+width, row) {
+				width++
+			}
+
+			height := 1
+		rows:
+			for row+height < tileMap.rows {
+				for c := col; c < col+width; c++ {
+					if !mergeable(c, row+height) {
+						break rows
+					}
+				}
+
+				height++
+			}
+
+			for r := row; r < row+height; r++ {
+				for c := col; c < col+width; c++ {
+					merged[r*tileMap.cols+c] = true
+				}
+			}
+
+			rect := tileMap.TileRect(col, row)
+			rect.W, rect.H = float32(width)*tileMap.TileSize, float32(height)*tileMap.TileSize
+			rects = append(rects, rect)
+		}
+	}
+
+	return rects
+}
+
+// Returns the range of tiles that the rect overlaps, which can be outside of the tile map
+func (tileMap *TileMap) tileRange(rect quadtree.Rect) (int, int, int, int) {
+	toTile := func(pos float32, origin float32) float64 {
+		return float64((pos - origin) / tileMap.TileSize)
+	}
+
+	// a rect touching the edge of a tile does not overlap it
+	minCol := int(math.Floor(toTile(rect.X, tileMap.Origin.X)))
+	minRow := int(math.Floor(toTile(rect.Y, tileMap.Origin.Y)))
+	maxCol := int(math.Ceil(toTile(rect.Right(), tileMap.Origin.X))) - 1
+	maxRow := int(math.Ceil(toTile(rect.Bottom(), tileMap.Origin.Y))) - 1
+
+	return minCol, minRow, maxCol, maxRow
+}
+
+// Returns whether the rect overlaps any solid or slope tile
+func (tileMap *TileMap) Overlaps(rect quadtree.Rect) bool {
+	minCol, minRow, maxCol, maxRow := tileMap.tileRange(rect)
+	probe := rectProbe(rect)
+
+	for row := max(minRow, 0); row <= min(maxRow, tileMap.rows-1); row++ {
+		for col := max(minCol, 0); col <= min(maxCol, tileMap.cols-1); col++ {
+			if tileMap.IsSolid(col, row) {
+				return true
+			}
+
+			if slope, ok := tileMap.slopeCollider(col, row); ok && probe.Overlaps(&slope) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Returns the first solid or slope tile that the rect hits when moved in the direction by up to maxDistance,
+// and false when it does not hit any tile. A rect without a size sweeps as a ray.
+func (tileMap *TileMap) Sweep(rect quadtree.Rect, direction util.Vec2[float32], maxDistance float32) (TileHit, bool) {
+	if direction.Length() == 0 {
+		panic("cannot cast without a direction")
+	}
+
+	direction = direction.Normalize()
+	end := rect
+	end.X, end.Y = rect.X+direction.X*maxDistance, rect.Y+direction.Y*maxDistance
+	minCol, minRow, maxCol, maxRow := tileMap.tileRange(rect.Union(end))
+
+	closest, found := TileHit{}, false
+	for row := max(minRow, 0); row <= min(maxRow, tileMap.rows-1); row++ {
+		for col := max(minCol, 0); col <= min(maxCol, tileMap.cols-1); col++ {
+			var distance float32
+			var normal vec
+			var ok bool
+
+			if slope, isSlope := tileMap.slopeCollider(col, row); isSlope {
+				probe := rectProbe(rect)
+
+				var hit Hit
+				hit, ok = sweep(&probe, probe.Pos, direction, maxDistance, &slope)
+				distance, normal = hit.Distance, hit.Normal
+			} else if tileMap.IsSolid(col, row) {
+				distance, normal, ok = sweepRects(rect, direction, tileMap.TileRect(col, row))
+			}
+
+			if ok && distance <= maxDistance && (!found || distance < closest.Distance) {
+				closest, found = TileHit{col, row, distance, normal}, true
+			}
+		}
+	}
+
+	return closest, found
+}
+
+// an unregistered collider covering the rect, for testing against slope tiles with the narrow phase.
+// Rects without an area are lines, which are capsules without a radius rather than polygons without an area.
+func rectProbe(rect quadtree.Rect) Collider {
+	probe := Collider{Rect: rect}
+	probe.Pos = vec{X: rect.X, Y: rect.Y}
+
+	if rect.W == 0 || rect.H == 0 {
+		probe.shape = Capsule{B: vec{X: rect.W, Y: rect.H}}
+	}
+
+	return probe
+}
+
+// Slab test of the top left corner of rect moving along the direction against the other rect grown by the size of rect.
+// Returns how far rect moves before touching other and the side of other it touches.
+func sweepRects(rect quadtree.Rect, direction vec, other quadtree.Rect) (float32, vec, bool) {
+	entry, exit := float32(math.Inf(-1)), float32(math.Inf(1))
+	var normal vec
+
+	slab := func(pos float32, dir float32, lower float32, upper float32, axis vec) bool {
+		if dir == 0 {
+			// moving along the slab, touching its sides is not overlapping
+			return pos > lower && pos < upper
+		}
+
+		near, far := (lower-pos)/dir, (upper-pos)/dir
+		if near > far {
+			near, far = far, near
+		}
+
+		if near > entry {
+			entry = near
+			normal = axis.Multiply(-sign(dir))
+		}
+
+		exit = min(exit, far)
+		return true
+	}
+
+	if !slab(rect.X, direction.X, other.X-rect.W, other.Right(), vec{X: 1, Y: 0}) ||
+		!slab(rect.Y, direction.Y, other.Y-rect.H, other.Bottom(), vec{X: 0, Y: 1}) {
+		return 0, vec{}, false
+	}
+
+	if entry >= exit || exit <= 0 {
+		return 0, vec{}, false
+	}
+
+	if entry < 0 {
+		// already overlapping, so the rect is pushed out through the side it overlaps the least
+		return 0, leastOverlappedSide(rect, other), true
+	}
+
+	return entry, normal, true
+}
+
+func leastOverlappedSide(rect quadtree.Rect, other quadtree.Rect) vec {
+	sides := [4]struct {
+		overlap float32
+		normal  vec
+	}{
+		{rect.Right() - other.X, vec{X: -1, Y: 0}},
+		{other.Right() - rect.X, vec{X: 1, Y: 0}},
+		{rect.Bottom() - other.Y, vec{X: 0, Y: -1}},
+		{other.Bottom() - rect.Y, vec{X: 0, Y: 1}},
+	}
+
+	least := sides[0]
+	for _, side := range sides[1:] {
+		if side.overlap < least.overlap {
+			least = side
+		}
+	}
+
+	return least.normal
+}
+
+func sign(a float32) float32 {
+	if a < 0 {
+		return -1
+	}
+
+	return 1
+}
@@ -0,0 +1,249 @@
+package collision
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/TheRaizer/GolangGame/core"
+	"github.com/TheRaizer/GolangGame/util"
+	"github.com/TheRaizer/GolangGame/util/datastructures/quadtree"
+	"github.com/stretchr/testify/require"
+)
+
+// creates a tile map of 10x10 tiles at the origin, where # marks a solid tile
+func newTestTileMap(collisionSys *CollisionSystem, rows ...string) TileMap {
+	tileMap := NewTileMap(core.WALL_LAYER, "tiles", vec{}, 10, len(rows[0]), len(rows), collisionSys)
+	for row, tiles := range rows {
+		for col, tile := range tiles {
+			tileMap.SetSolid(col, row, tile == '#')
+		}
+	}
+
+	return tileMap
+}
+
+func TestTileMapBuildShouldMergeTiles(t *testing.T) {
+	const NAME string = "should merge the tiles of %v"
+	getName := func(input []string) string {
+		return fmt.Sprintf(NAME, input)
+	}
+
+	cases := []util.TestCase[[]string, []quadtree.Rect]{
+		{Name: getName, Input: []string{"...", "...", "..."}, Expected: []quadtree.Rect{}},
+		{Name: getName, Input: []string{"###", "###"}, Expected: []quadtree.Rect{{X: 0, Y: 0, W: 30, H: 20}}},
+		{
+			Name:  getName,
+			Input: []string{"#..", "#..", "###"},
+			Expected: []quadtree.Rect{
+				{X: 0, Y: 0, W: 10, H: 30},
+				{X: 10, Y: 20, W: 20, H: 10},
+			},
+		},
+		{
+			Name:  getName,
+			Input: []string{"##.#", "##.#", "...."},
+			Expected: []quadtree.Rect{
+				{X: 0, Y: 0, W: 20, H: 20},
+				{X: 30, Y: 0, W: 10, H: 20},
+			},
+		},
+		{
+			Name:  getName,
+			Input: []string{"#.#", ".#.", "#.#"},
+			Expected: []quadtree.Rect{
+				{X: 0, Y: 0, W: 10, H: 10},
+				{X: 20, Y: 0, W: 10, H: 10},
+				{X: 10, Y: 10, W: 10, H: 10},
+				{X: 0, Y: 20, W: 10, H: 10},
+				{X: 20, Y: 20, W: 10, H: 10},
+			},
+		},
+	}
+
+	util.IterateTestCases(cases, t, func(testCase util.TestCase[[]string, []quadtree.Rect]) {
+		collisionSys := NewCollisionSystem(quadtree.Rect{X: 0, Y: 0, W: 200, H: 200})
+		tileMap := newTestTileMap(&collisionSys, testCase.Input...)
+		tileMap.Build()
+
+		rects := make([]quadtree.Rect, 0)
+		for _, collider := range tileMap.Colliders() {
+			rects = append(rects, collider.Rect)
+		}
+
+		require.Equal(t, testCase.Expected, rects)
+	})
+}
+
+func TestTileMapShouldBeCollidedWithOnceBuilt(t *testing.T) {
+	collisionSys := NewCollisionSystem(quadtree.Rect{X: 0, Y: 0, W: 200, H: 200})
+	tileMap := newTestTileMap(&collisionSys,
+		"....",
+		"....",
+		"####",
+	)
+
+	player := NewCollider(core.PLAYER_LAYER, "player", quadtree.Rect{X: 5, Y: 15, W: 10, H: 10}, &collisionSys, &collisionSys, nil, nil)
+	contacts := make([]string, 0)
+	player.AddCollisionEnterEvent(func(collision Collision) {
+		contacts = append(contacts, collision.Other.ID())
+	})
+
+	collisionSys.OnLoop()
+	require.Empty(t, contacts)
+
+	tileMap.Build()
+	collisionSys.OnLoop()
+	require.Equal(t, []string{"tiles_0"}, contacts)
+
+	hit, ok := collisionSys.Raycast(vec{X: 35, Y: 0}, vec{X: 0, Y: 1}, 100, QueryFilter{Mask: ALL_LAYERS})
+	require.True(t, ok)
+	require.Equal(t, "tiles_0", hit.Collider.ID())
+	require.Equal(t, float32(20), hit.Distance)
+
+	// rebuilding replaces the colliders of the previous build
+	previous := tileMap.Colliders()
+	tileMap.SetSolid(0, 2, false)
+	tileMap.Build()
+	require.Len(t, tileMap.Colliders(), 1)
+	require.Equal(t, quadtree.Rect{X: 10, Y: 20, W: 30, H: 10}, tileMap.Colliders()[0].Rect)
+	require.Len(t, collisionSys.staticColliders, 1)
+
+	// without changing the colliders returned for the previous build
+	require.Equal(t, quadtree.Rect{X: 0, Y: 20, W: 40, H: 10}, previous[0].Rect)
+}
+
+func TestTileMapOverlaps(t *testing.T) {
+	collisionSys := NewCollisionSystem(quadtree.Rect{X: 0, Y: 0, W: 200, H: 200})
+	tileMap := newTestTileMap(&collisionSys,
+		"....",
+		".#..",
+		"....",
+	)
+
+	const NAME string = "should return whether %+v overlaps a solid tile"
+	getName := func(input quadtree.Rect) string {
+		return fmt.Sprintf(NAME, input)
+	}
+
+	cases := []util.TestCase[quadtree.Rect, bool]{
+		{Name: getName, Input: quadtree.Rect{X: 12, Y: 12, W: 2, H: 2}, Expected: true},
+		{Name: getName, Input: quadtree.Rect{X: 0, Y: 0, W: 11, H: 11}, Expected: true},
+		// touching the sides of the tile
+		{Name: getName, Input: quadtree.Rect{X: 0, Y: 0, W: 10, H: 30}, Expected: false},
+		{Name: getName, Input: quadtree.Rect{X: 20, Y: 10, W: 10, H: 10}, Expected: false},
+		{Name: getName, Input: quadtree.Rect{X: 10, Y: 20, W: 10, H: 10}, Expected: false},
+		// outside of the tile map
+		{Name: getName, Input: quadtree.Rect{X: -50, Y: -50, W: 30, H: 30}, Expected: false},
+		{Name: getName, Input: quadtree.Rect{X: -50, Y: -50, W: 500, H: 500}, Expected: true},
+	}
+
+	util.IterateTestCases(cases, t, func(testCase util.TestCase[quadtree.Rect, bool]) {
+		require.Equal(t, testCase.Expected, tileMap.Overlaps(testCase.Input))
+	})
+}
+
+func TestTileMapSweep(t *testing.T) {
+	collisionSys := NewCollisionSystem(quadtree.Rect{X: 0, Y: 0, W: 200, H: 200})
+	tileMap := newTestTileMap(&collisionSys,
+		".....#",
+		"......",
+		"######",
+	)
+
+	type TestInput struct {
+		rect        quadtree.Rect
+		direction   vec
+		maxDistance float32
+	}
+
+	type TestExpected struct {
+		hit TileHit
+		ok  bool
+	}
+
+	const NAME string = "should sweep %+v"
+	getName := func(input TestInput) string {
+		return fmt.Sprintf(NAME, input)
+	}
+
+	cases := []util.TestCase[TestInput, TestExpected]{
+		// falling on to the floor
+		{Name: getName, Input: TestInput{quadtree.Rect{X: 12, Y: 0, W: 5, H: 5}, vec{X: 0, Y: 1}, 100}, Expected: TestExpected{TileHit{1, 2, 15, vec{X: 0, Y: -1}}, true}},
+		{Name: getName, Input: TestInput{quadtree.Rect{X: 12, Y: 0, W: 5, H: 5}, vec{X: 0, Y: 1}, 10}, Expected: TestExpected{}},
+		// sliding along the floor in to the wall
+		{Name: getName, Input: TestInput{quadtree.Rect{X: 0, Y: 15, W: 5, H: 5}, vec{X: 1, Y: 0}, 100}, Expected: TestExpected{}},
+		{Name: getName, Input: TestInput{quadtree.Rect{X: 0, Y: 0, W: 5, H: 5}, vec{X: 1, Y: 0}, 100}, Expected: TestExpected{TileHit{5, 0, 45, vec{X: -1, Y: 0}}, true}},
+		// a ray
+		{Name: getName, Input: TestInput{quadtree.Rect{X: 0, Y: 0}, vec{X: 3, Y: 4}, 100}, Expected: TestExpected{TileHit{1, 2, 25, vec{X: 0, Y: -1}}, true}},
+		// moving away from an overlapped tile still hits it
+		{Name: getName, Input: TestInput{quadtree.Rect{X: 0, Y: 18, W: 5, H: 5}, vec{X: 0, Y: -1}, 100}, Expected: TestExpected{TileHit{0, 2, 0, vec{X: 0, Y: -1}}, true}},
+	}
+
+	util.IterateTestCases(cases, t, func(testCase util.TestCase[TestInput, TestExpected]) {
+		hit, ok := tileMap.Sweep(testCase.Input.rect, testCase.Input.direction, testCase.Input.maxDistance)
+
+		require.Equal(t, testCase.Expected.ok, ok)
+		require.Equal(t, testCase.Expected.hit, hit)
+	})
+}
+
+func TestTileMapSlopes(t *testing.T) {
+	collisionSys := NewCollisionSystem(quadtree.Rect{X: 0, Y: 0, W: 200, H: 200})
+	tileMap := newTestTileMap(&collisionSys,
+		"...",
+		"###",
+	)
+	tileMap.SetSlope(1, 0, SLOPE_45, true)
+	tileMap.SetSlope(2, 0, SLOPE_22_5, false)
+
+	// the slopes sit on the bottom of their tiles
+	slope, ok := tileMap.Slope(2, 0)
+	require.True(t, ok)
+	require.InDelta(t, 10-10*math.Tan(SLOPE_22_5), slope.Bounds(vec{}).Y, 1e-5)
+
+	_, ok = tileMap.Slope(0, 0)
+	require.False(t, ok)
+
+	tileMap.Build()
+	require.Len(t, tileMap.Colliders(), 3)
+	require.Equal(t, "tiles_1", tileMap.Colliders()[1].ID())
+	require.Equal(t, slope, tileMap.Colliders()[2].Shape())
+
+	// only the part of the tile under the slope is solid
+	require.True(t, tileMap.Overlaps(quadtree.Rect{X: 17, Y: 7, W: 2, H: 2}))
+	require.False(t, tileMap.Overlaps(quadtree.Rect{X: 11, Y: 1, W: 2, H: 2}))
+	require.True(t, tileMap.Overlaps(quadtree.Rect{X: 21, Y: 8, W: 2, H: 1}))
+	require.False(t, tileMap.Overlaps(quadtree.Rect{X: 24, Y: 2, W: 2, H: 2}))
+
+	hit, ok := tileMap.Sweep(quadtree.Rect{X: 15, Y: -10}, vec{X: 0, Y: 1}, 100)
+	require.True(t, ok)
+	require.Equal(t, 1, hit.Col)
+	require.Equal(t, 0, hit.Row)
+	require.InDelta(t, 15, hit.Distance, castTolerance)
+	require.InDelta(t, -1/sqrt2, hit.Normal.X, 1e-5)
+	require.InDelta(t, -1/sqrt2, hit.Normal.Y, 1e-5)
+
+	// making a slope solid removes the slope
+	tileMap.SetSolid(1, 0, true)
+	_, ok = tileMap.Slope(1, 0)
+	require.False(t, ok)
+}
+
+func TestSetSlopeShouldPanicWhenSteeperThanTheTile(t *testing.T) {
+	collisionSys := NewCollisionSystem(quadtree.Rect{X: 0, Y: 0, W: 200, H: 200})
+	tileMap := NewTileMap(core.WALL_LAYER, "tiles", vec{}, 10, 3, 3, &collisionSys)
+
+	require.PanicsWithValue(t, "slope tiles can be at most 45 degrees", func() {
+		tileMap.SetSlope(0, 0, math.Pi/3, true)
+	})
+}
+
+func TestSetSolidShouldPanicOutsideOfTileMap(t *testing.T) {
+	collisionSys := NewCollisionSystem(quadtree.Rect{X: 0, Y: 0, W: 200, H: 200})
+	tileMap := NewTileMap(core.WALL_LAYER, "tiles", vec{}, 10, 3, 3, &collisionSys)
+
+	require.PanicsWithValue(t, "tile 3, 0 is outside of the tile map", func() {
+		tileMap.SetSolid(3, 0, true)
+	})
+}
@@ -12,6 +12,7 @@ import (
 
 type Game struct {
 	collisionSys core.System[*collision.Collider]
+	simulations  []core.Simulation
 
 	gameObjects map[string]core.GameObject
 	screen      image.Gray
@@ -89,6 +90,10 @@ func (game *Game) loop() {
 				gameObject.OnUpdate(uint64(msPerUpdate), game.surface)
 			}
 
+			for _, simulation := range game.simulations {
+				simulation.Step(uint64(msPerUpdate))
+			}
+
 			lag -= msPerUpdate
 		}
 
@@ -116,6 +121,11 @@ func (game *Game) Quit() {
 	game.running = false
 }
 
+// Steps the simulation after the game objects every update, such as a joint system solving the joints between rigid bodies
+func (game *Game) AddSimulation(simulation core.Simulation) {
+	game.simulations = append(game.simulations, simulation)
+}
+
 func (game *Game) AddGameObject(gameObject core.GameObject) {
 	if game.gameObjects[gameObject.ID()] != nil {
 		panic("duplicate id: " + gameObject.ID())
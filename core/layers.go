@@ -1,6 +1,142 @@
 package core
 
+import "fmt"
+
 const (
 	WALL_LAYER   = iota
 	PLAYER_LAYER = iota
+	ENEMY_LAYER
+	PICKUP_LAYER
+	PROJECTILE_LAYER
+	PLATFORM_LAYER // one-way platforms
+)
+
+// layers are used as bit indices of uint32 layer masks
+const MAX_LAYERS = 32
+
+// How the colliders of two layers interact when they overlap
+type Interaction uint8
+
+const (
+	COLLIDE Interaction = iota // the colliders block each other and are told about the collision
+	TRIGGER                    // the colliders pass through each other but are still told about the overlap
+	IGNORE                     // the colliders pass through each other and are not told about the overlap
 )
+
+func (interaction Interaction) String() string {
+	switch interaction {
+	case COLLIDE:
+		return "collide"
+	case TRIGGER:
+		return "trigger"
+	case IGNORE:
+		return "ignore"
+	}
+
+	return fmt.Sprintf("Interaction(%d)", uint8(interaction))
+}
+
+// Names the layers and holds the layer-vs-layer collision matrix.
+// The matrix is symmetric, setting how layer a interacts with layer b also sets how b interacts with a.
+type LayerRegistry struct {
+	names  []string
+	matrix [MAX_LAYERS][MAX_LAYERS]Interaction
+}
+
+// Creates a registry with the built in layers registered at their constant indices.
+// Pickups only trigger with the player, projectiles pass through pickups and each other,
+// and one-way platforms do not interact with walls or other platforms.
+func NewLayerRegistry() LayerRegistry {
+	registry := LayerRegistry{}
+	registry.Register("wall")
+	registry.Register("player")
+	registry.Register("enemy")
+	registry.Register("pickup")
+	registry.Register("projectile")
+	registry.Register("platform")
+
+	for layer := range registry.names {
+		registry.SetInteraction(PICKUP_LAYER, layer, IGNORE)
+	}
+	registry.SetInteraction(PICKUP_LAYER, PLAYER_LAYER, TRIGGER)
+
+	registry.SetInteraction(PROJECTILE_LAYER, PICKUP_LAYER, IGNORE)
+	registry.SetInteraction(PLATFORM_LAYER, WALL_LAYER, IGNORE)
+
+	return registry
+}
+
+// Registers a new layer and returns its index.
+// A new layer collides with every other layer and ignores itself, the same as the layers before the matrix existed.
+func (registry *LayerRegistry) Register(name string) int {
+	if _, ok := registry.LayerByName(name); ok {
+		panic("layer is already registered: " + name)
+	}
+
+	if len(registry.names) == MAX_LAYERS {
+		panic(fmt.Sprintf("cannot register more than %d layers", MAX_LAYERS))
+	}
+
+	layer := len(registry.names)
+	registry.names = append(registry.names, name)
+
+	for other := range registry.names {
+		registry.SetInteraction(layer, other, COLLIDE)
+	}
+	registry.SetInteraction(layer, layer, IGNORE)
+
+	return layer
+}
+
+// Returns the index of the layer with the given name, and false if there is no such layer
+func (registry *LayerRegistry) LayerByName(name string) (int, bool) {
+	for layer, layerName := range registry.names {
+		if layerName == name {
+			return layer, true
+		}
+	}
+
+	return -1, false
+}
+
+func (registry *LayerRegistry) Name(layer int) string {
+	registry.checkLayer(layer)
+	return registry.names[layer]
+}
+
+// Sets how the colliders of the two layers interact with each other
+func (registry *LayerRegistry) SetInteraction(layer int, otherLayer int, interaction Interaction) {
+	registry.checkLayer(layer)
+	registry.checkLayer(otherLayer)
+
+	registry.matrix[layer][otherLayer] = interaction
+	registry.matrix[otherLayer][layer] = interaction
+}
+
+// Returns how the colliders of the two layers interact with each other
+func (registry *LayerRegistry) Interaction(layer int, otherLayer int) Interaction {
+	registry.checkLayer(layer)
+	registry.checkLayer(otherLayer)
+
+	return registry.matrix[layer][otherLayer]
+}
+
+// Returns the mask of the layers that have the given interaction with layer
+func (registry *LayerRegistry) Mask(layer int, interaction Interaction) uint32 {
+	registry.checkLayer(layer)
+
+	var mask uint32
+	for other := range registry.names {
+		if registry.matrix[layer][other] == interaction {
+			mask |= 1 << other
+		}
+	}
+
+	return mask
+}
+
+func (registry *LayerRegistry) checkLayer(layer int) {
+	if layer < 0 || layer >= len(registry.names) {
+		panic(fmt.Sprintf("layer %d is not registered", layer))
+	}
+}
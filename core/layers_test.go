@@ -0,0 +1,82 @@
+package core
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/TheRaizer/GolangGame/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewLayerRegistryShouldRegisterBuiltInLayers(t *testing.T) {
+	registry := NewLayerRegistry()
+
+	for layer, name := range []string{"wall", "player", "enemy", "pickup", "projectile", "platform"} {
+		found, ok := registry.LayerByName(name)
+		require.True(t, ok)
+		require.Equal(t, layer, found)
+		require.Equal(t, name, registry.Name(layer))
+	}
+}
+
+func TestDefaultInteractions(t *testing.T) {
+	type TestInput struct {
+		layer      int
+		otherLayer int
+	}
+
+	const NAME string = "layer %d should have the default interaction with layer %d"
+	getName := func(input TestInput) string {
+		return fmt.Sprintf(NAME, input.layer, input.otherLayer)
+	}
+
+	cases := []util.TestCase[TestInput, Interaction]{
+		{Name: getName, Input: TestInput{PLAYER_LAYER, WALL_LAYER}, Expected: COLLIDE},
+		{Name: getName, Input: TestInput{PLAYER_LAYER, PLAYER_LAYER}, Expected: IGNORE},
+		{Name: getName, Input: TestInput{ENEMY_LAYER, PLAYER_LAYER}, Expected: COLLIDE},
+		{Name: getName, Input: TestInput{PICKUP_LAYER, PLAYER_LAYER}, Expected: TRIGGER},
+		{Name: getName, Input: TestInput{PLAYER_LAYER, PICKUP_LAYER}, Expected: TRIGGER},
+		{Name: getName, Input: TestInput{PICKUP_LAYER, ENEMY_LAYER}, Expected: IGNORE},
+		{Name: getName, Input: TestInput{PROJECTILE_LAYER, ENEMY_LAYER}, Expected: COLLIDE},
+		{Name: getName, Input: TestInput{PROJECTILE_LAYER, PROJECTILE_LAYER}, Expected: IGNORE},
+		{Name: getName, Input: TestInput{PLATFORM_LAYER, WALL_LAYER}, Expected: IGNORE},
+		{Name: getName, Input: TestInput{PLATFORM_LAYER, PLAYER_LAYER}, Expected: COLLIDE},
+	}
+
+	registry := NewLayerRegistry()
+	util.IterateTestCases(cases, t, func(testCase util.TestCase[TestInput, Interaction]) {
+		require.Equal(t, testCase.Expected, registry.Interaction(testCase.Input.layer, testCase.Input.otherLayer))
+	})
+}
+
+func TestRegisterShouldAddLayerWithDefaultInteractions(t *testing.T) {
+	registry := NewLayerRegistry()
+
+	water := registry.Register("water")
+	registry.SetInteraction(water, PLAYER_LAYER, TRIGGER)
+
+	require.Equal(t, PLATFORM_LAYER+1, water)
+	require.Equal(t, IGNORE, registry.Interaction(water, water))
+	require.Equal(t, COLLIDE, registry.Interaction(WALL_LAYER, water))
+	require.Equal(t, TRIGGER, registry.Interaction(PLAYER_LAYER, water))
+	require.Equal(t, uint32(1<<PLAYER_LAYER), registry.Mask(water, TRIGGER))
+	require.Equal(t, uint32(1<<water), registry.Mask(water, IGNORE))
+}
+
+func TestRegisterShouldPanic(t *testing.T) {
+	registry := NewLayerRegistry()
+	require.PanicsWithValue(t, "layer is already registered: wall", func() {
+		registry.Register("wall")
+	})
+
+	for i := len(registry.names); i < MAX_LAYERS; i++ {
+		registry.Register(fmt.Sprintf("layer%d", i))
+	}
+	require.PanicsWithValue(t, "cannot register more than 32 layers", func() {
+		registry.Register("one too many")
+	})
+
+	require.PanicsWithValue(t, "layer 32 is not registered", func() {
+		registry.Interaction(0, 32)
+	})
+}
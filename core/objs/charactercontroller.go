@@ -0,0 +1,147 @@
+package objs
+
+import (
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// Tunes how a character controller moves, in pixels, seconds and milliseconds
+type CharacterConfig struct {
+	MaxSpeed          float32 // the fastest the character runs, in pixels per second
+	Acceleration      float32 // how quickly the character speeds up towards the direction it is moved in
+	Deceleration      float32 // how quickly the character slows down once it is no longer moved
+	TurnAcceleration  float32 // how quickly the character speeds up when moved against the direction it is running in
+	AirControl        float32 // the fraction of the acceleration and deceleration the character has in the air, from 0 to 1
+	JumpSpeed         float32 // the upwards speed the character jumps with, in pixels per second
+	JumpCutMultiplier float32 // what the upwards speed is multiplied by when jump is released early, for lower jumps
+	MaxFallSpeed      float32 // the fastest the character falls, in pixels per second
+	CoyoteTime        uint64  // how many milliseconds the character can still jump for after running off of a ledge
+	JumpBufferTime    uint64  // how many milliseconds a jump pressed before landing is remembered for
+}
+
+// Returns a config for a responsive character, for gravity of around 1500 pixels per second squared
+func DefaultCharacterConfig() CharacterConfig {
+	return CharacterConfig{
+		MaxSpeed:          200,
+		Acceleration:      1500,
+		Deceleration:      2000,
+		TurnAcceleration:  3000,
+		AirControl:        0.6,
+		JumpSpeed:         550,
+		JumpCutMultiplier: 0.5,
+		MaxFallSpeed:      700,
+		CoyoteTime:        100,
+		JumpBufferTime:    100,
+	}
+}
+
+// Moves a rigid body like the character of a platformer, from the input of a player or the decisions of an NPC.
+// The controller updates the rigid body itself, after steering it, so add the owner of the controller to the game
+// rather than the rigid body.
+type CharacterController struct {
+	Config CharacterConfig
+
+	rb           *RigidBody
+	move         float32 // the direction the character is moved in, from -1 for left to 1 for right
+	isJumpHeld   bool
+	isJumping    bool   // whether the character is rising from a jump that can still be cut short
+	coyoteTimer  uint64 // the milliseconds left to jump in after leaving the ground
+	bufferTimer  uint64 // the milliseconds left that a pressed jump is remembered for
+	isJumpQueued bool   // whether a jump was pressed and is yet to be used, even when it is not buffered
+}
+
+func NewCharacterController(rb *RigidBody, config CharacterConfig) CharacterController {
+	return CharacterController{
+		Config: config,
+		rb:     rb,
+	}
+}
+
+// Moves the character in the direction, from -1 for left to 1 for right, until it is moved in a different direction
+func (controller *CharacterController) SetMove(direction float32) {
+	controller.move = min(max(direction, -1), 1)
+}
+
+// Jumps when the character is on the ground, or as soon as it lands within the jump buffer time.
+// The jump is as high as possible when jump is held until the character starts falling.
+func (controller *CharacterController) PressJump() {
+	controller.isJumpHeld = true
+	controller.isJumpQueued = true
+	controller.bufferTimer = controller.Config.JumpBufferTime
+}
+
+// Cuts the current jump short
+func (controller *CharacterController) ReleaseJump() {
+	controller.isJumpHeld = false
+}
+
+// Drops the character through the one-way platforms it is standing on, ignoring them for the milliseconds
+func (controller *CharacterController) DropThrough(duration uint64) {
+	controller.rb.DropThrough(duration)
+}
+
+// Whether the character was left standing on the ground by the last update
+func (controller *CharacterController) IsGrounded() bool {
+	return controller.rb.IsGrounded()
+}
+
+// Steers the velocity of the rigid body towards the input and then updates the rigid body
+func (controller *CharacterController) OnUpdate(dt uint64, surface *sdl.Surface) {
+	config := controller.Config
+	seconds := float32(dt) / 1000
+	velocity := controller.rb.Velocity
+	isGrounded := controller.rb.IsGrounded()
+
+	if isGrounded {
+		controller.coyoteTimer = config.CoyoteTime
+		if velocity.Y >= 0 {
+			controller.isJumping = false
+		}
+	}
+
+	rate := config.Acceleration
+	if controller.move == 0 {
+		rate = config.Deceleration
+	} else if velocity.X != 0 && (velocity.X > 0) != (controller.move > 0) {
+		rate = config.TurnAcceleration
+	}
+
+	if !isGrounded {
+		rate *= config.AirControl
+	}
+
+	velocity.X = approach(velocity.X, controller.move*config.MaxSpeed, rate*seconds)
+
+	canJump := isGrounded || controller.coyoteTimer > 0
+	if controller.isJumpQueued && canJump {
+		velocity.Y = -config.JumpSpeed
+		controller.isJumping = true
+		controller.isJumpQueued = false
+		controller.coyoteTimer = 0
+	}
+
+	// releasing jump while still rising lowers the jump, once per jump
+	if controller.isJumping && !controller.isJumpHeld && velocity.Y < 0 {
+		velocity.Y *= config.JumpCutMultiplier
+		controller.isJumping = false
+	}
+
+	controller.rb.SetMaxFallSpeed(config.MaxFallSpeed)
+	controller.rb.OnUpdate(dt, surface)
+
+	controller.coyoteTimer -= min(controller.coyoteTimer, dt)
+	if controller.bufferTimer > dt {
+		controller.bufferTimer -= dt
+	} else {
+		controller.bufferTimer = 0
+		controller.isJumpQueued = false
+	}
+}
+
+// Moves the value towards the target by at most delta
+func approach(value float32, target float32, delta float32) float32 {
+	if value < target {
+		return min(value+delta, target)
+	}
+
+	return max(value-delta, target)
+}
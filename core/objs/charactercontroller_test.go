@@ -0,0 +1,166 @@
+package objs
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/TheRaizer/GolangGame/core"
+	"github.com/TheRaizer/GolangGame/core/collision"
+	"github.com/TheRaizer/GolangGame/util"
+	"github.com/TheRaizer/GolangGame/util/datastructures/quadtree"
+	"github.com/stretchr/testify/require"
+)
+
+// creates a character with the default config above a floor at y 100, under a gravity of 1500
+func newCharacterAboveFloor(pos util.Vec2[float32], velocity util.Vec2[float32]) (*CharacterController, *collision.Collider) {
+	collisionSys := collision.NewCollisionSystem(quadtree.Rect{X: -500, Y: -500, W: 1000, H: 1000})
+	world := PhysicsWorld{Gravity: util.Vec2[float32]{X: 0, Y: 1500}}
+	floor := collision.NewCollider(core.WALL_LAYER, "floor", quadtree.Rect{X: -500, Y: 100, W: 1000, H: 100}, &collisionSys, &collisionSys, nil, nil)
+
+	rb, _ := newRigidBodyInScene(core.PLAYER_LAYER, "player", pos, velocity, &collisionSys, &world, false)
+	controller := NewCharacterController(rb, DefaultCharacterConfig())
+
+	return &controller, floor
+}
+
+func TestCharacterControllerShouldAccelerateTowardsTheMove(t *testing.T) {
+	type TestInput struct {
+		isGrounded bool
+		velocityX  float32
+		move       float32
+	}
+
+	const NAME string = "should change the horizontal velocity with %+v"
+	getName := func(input TestInput) string {
+		return fmt.Sprintf(NAME, input)
+	}
+
+	cases := []util.TestCase[TestInput, float32]{
+		// accelerating up to the max speed
+		{Name: getName, Input: TestInput{true, 0, 1}, Expected: 150},
+		{Name: getName, Input: TestInput{true, 150, 1}, Expected: 200},
+		{Name: getName, Input: TestInput{true, 0, 0.5}, Expected: 100},
+		// decelerating to a stop
+		{Name: getName, Input: TestInput{true, 200, 0}, Expected: 0},
+		{Name: getName, Input: TestInput{true, -300, 0}, Expected: -100},
+		// turning around
+		{Name: getName, Input: TestInput{true, 200, -1}, Expected: -100},
+		// with less control in the air
+		{Name: getName, Input: TestInput{false, 0, 1}, Expected: 90},
+		{Name: getName, Input: TestInput{false, 200, 0}, Expected: 80},
+	}
+
+	util.IterateTestCases(cases, t, func(testCase util.TestCase[TestInput, float32]) {
+		pos := util.Vec2[float32]{X: 0, Y: 0}
+		if testCase.Input.isGrounded {
+			pos.Y = 90
+		}
+
+		controller, _ := newCharacterAboveFloor(pos, util.Vec2[float32]{})
+		// lands on the floor when grounded
+		controller.OnUpdate(10, nil)
+		require.Equal(t, testCase.Input.isGrounded, controller.IsGrounded())
+
+		controller.rb.Velocity.X = testCase.Input.velocityX
+		controller.SetMove(testCase.Input.move)
+		controller.OnUpdate(100, nil)
+
+		require.InDelta(t, testCase.Expected, controller.rb.Velocity.X, 1e-3)
+	})
+}
+
+func TestCharacterControllerShouldJumpFromTheGround(t *testing.T) {
+	controller, _ := newCharacterAboveFloor(util.Vec2[float32]{X: 0, Y: 90}, util.Vec2[float32]{})
+	controller.OnUpdate(10, nil)
+
+	controller.PressJump()
+	controller.OnUpdate(10, nil)
+
+	require.InDelta(t, -535, controller.rb.Velocity.Y, 1e-3)
+	require.False(t, controller.IsGrounded())
+
+	// jumping again in the air does nothing
+	controller.PressJump()
+	controller.OnUpdate(10, nil)
+
+	require.InDelta(t, -520, controller.rb.Velocity.Y, 1e-3)
+}
+
+func TestCharacterControllerShouldJumpWithinTheCoyoteTime(t *testing.T) {
+	const NAME string = "should jump %d milliseconds after leaving the ground: %v"
+	getName := func(input uint64) string {
+		return fmt.Sprintf(NAME, input, input < 100)
+	}
+
+	cases := []util.TestCase[uint64, bool]{
+		{Name: getName, Input: 50, Expected: true},
+		{Name: getName, Input: 90, Expected: true},
+		{Name: getName, Input: 150, Expected: false},
+	}
+
+	util.IterateTestCases(cases, t, func(testCase util.TestCase[uint64, bool]) {
+		controller, floor := newCharacterAboveFloor(util.Vec2[float32]{X: 0, Y: 90}, util.Vec2[float32]{})
+		controller.OnUpdate(10, nil)
+
+		// the ground falls away from under the character
+		floor.Disable()
+		controller.OnUpdate(testCase.Input, nil)
+		require.False(t, controller.IsGrounded())
+
+		controller.PressJump()
+		controller.OnUpdate(10, nil)
+
+		require.Equal(t, testCase.Expected, controller.rb.Velocity.Y < 0)
+	})
+}
+
+func TestCharacterControllerShouldBufferJumpsBeforeLanding(t *testing.T) {
+	const NAME string = "should jump after landing %d milliseconds after jump was pressed: %v"
+	getName := func(input uint64) string {
+		return fmt.Sprintf(NAME, input, input < 100)
+	}
+
+	cases := []util.TestCase[uint64, bool]{
+		{Name: getName, Input: 20, Expected: true},
+		{Name: getName, Input: 120, Expected: false},
+	}
+
+	util.IterateTestCases(cases, t, func(testCase util.TestCase[uint64, bool]) {
+		controller, _ := newCharacterAboveFloor(util.Vec2[float32]{X: 0, Y: 89}, util.Vec2[float32]{X: 0, Y: 100})
+
+		controller.PressJump()
+		controller.OnUpdate(testCase.Input, nil)
+		require.True(t, controller.IsGrounded())
+
+		controller.OnUpdate(10, nil)
+
+		require.Equal(t, testCase.Expected, controller.rb.Velocity.Y < 0)
+	})
+}
+
+func TestCharacterControllerShouldCutJumpsWhenReleasedEarly(t *testing.T) {
+	const NAME string = "should keep rising at full speed when jump is held: %v"
+	getName := func(input bool) string {
+		return fmt.Sprintf(NAME, input)
+	}
+
+	cases := []util.TestCase[bool, float32]{
+		{Name: getName, Input: true, Expected: -520},
+		{Name: getName, Input: false, Expected: -252.5},
+	}
+
+	util.IterateTestCases(cases, t, func(testCase util.TestCase[bool, float32]) {
+		controller, _ := newCharacterAboveFloor(util.Vec2[float32]{X: 0, Y: 90}, util.Vec2[float32]{})
+		controller.OnUpdate(10, nil)
+
+		controller.PressJump()
+		controller.OnUpdate(10, nil)
+
+		if !testCase.Input {
+			controller.ReleaseJump()
+		}
+		controller.OnUpdate(10, nil)
+
+		require.InDelta(t, testCase.Expected, controller.rb.Velocity.Y, 1e-3)
+	})
+}
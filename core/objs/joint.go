@@ -0,0 +1,396 @@
+package objs
+
+import (
+	"math"
+
+	"github.com/TheRaizer/GolangGame/util"
+)
+
+// how far past its limits a joint can be and still be holding its bodies at them, such as a rope that is pulled taut
+const jointTolerance = 0.01
+
+// A constraint between a rigid body and another rigid body, or a point in the world, solved by a joint system.
+// Bodies do not rotate, so the anchors of a joint stay at the same offset from the centres of the bodies.
+type Joint interface {
+	BreakForce() float32
+	SetBreakForce(breakForce float32)
+	AddBreakEvent(event func())
+	IsBroken() bool
+	Break()
+	base() *baseJoint
+	prepare(seconds float32) // called once every step, before the joint is solved
+	solve()                  // called for every iteration of the solver, moving the bodies closer to satisfying the joint
+}
+
+type baseJoint struct {
+	body        *RigidBody
+	other       *RigidBody         // nil when the body is joined to a point in the world
+	anchor      util.Vec2[float32] // relative to the centre of the collider of the body
+	otherAnchor util.Vec2[float32] // relative to the centre of the collider of the other body, or the point in the world
+	breakForce  float32
+	impulse     util.Vec2[float32] // the sum of the impulses applied by the joint during the current step
+	normal      util.Vec2[float32] // the direction between the anchors at the end of the last step, zero before the first step
+	isBroken    bool
+	breakEvents []func()
+}
+
+func newBaseJoint(body *RigidBody, anchor util.Vec2[float32], other *RigidBody, otherAnchor util.Vec2[float32]) baseJoint {
+	if body == nil {
+		panic("a joint needs a rigid body to constrain")
+	}
+
+	return baseJoint{
+		body:        body,
+		other:       other,
+		anchor:      anchor,
+		otherAnchor: otherAnchor,
+		breakForce:  float32(math.Inf(1)),
+	}
+}
+
+func (joint *baseJoint) base() *baseJoint {
+	return joint
+}
+
+// Returns the force the joint can hold its bodies with before it breaks, which is infinite by default.
+// Forces are in units of mass times pixels per second squared, so holding up a body takes its mass times the gravity.
+func (joint *baseJoint) BreakForce() float32 {
+	return joint.breakForce
+}
+
+// Breaks the joint once it holds its bodies with more than the force over a step, such as the weight of the bodies it holds up
+func (joint *baseJoint) SetBreakForce(breakForce float32) {
+	if breakForce <= 0 {
+		panic("the break force of a joint must be positive")
+	}
+
+	joint.breakForce = breakForce
+}
+
+// Registers an event called when the joint breaks, such as for snapping a rope or dropping a held object
+func (joint *baseJoint) AddBreakEvent(event func()) {
+	joint.breakEvents = append(joint.breakEvents, event)
+}
+
+func (joint *baseJoint) IsBroken() bool {
+	return joint.isBroken
+}
+
+// Stops the joint from constraining its bodies, and removes it from the joint system on its next step
+func (joint *baseJoint) Break() {
+	if joint.isBroken {
+		return
+	}
+
+	joint.isBroken = true
+	for _, event := range joint.breakEvents {
+		event()
+	}
+}
+
+// Returns where the anchors of the joint are in the world
+func (joint *baseJoint) anchors() (util.Vec2[float32], util.Vec2[float32]) {
+	anchor := joint.body.collider.Rect.Center().Add(joint.anchor)
+	if joint.other == nil {
+		return anchor, joint.otherAnchor
+	}
+
+	return anchor, joint.other.collider.Rect.Center().Add(joint.otherAnchor)
+}
+
+// Returns the inverse masses of the bodies, where a point in the world cannot be moved
+func (joint *baseJoint) invMasses() (float32, float32) {
+	if joint.other == nil {
+		return 1 / joint.body.mass, 0
+	}
+
+	return 1 / joint.body.mass, 1 / joint.other.mass
+}
+
+// Returns the velocity of the other body relative to the body
+func (joint *baseJoint) relativeVelocity() util.Vec2[float32] {
+	if joint.other == nil {
+		return joint.body.Velocity.Multiply(-1)
+	}
+
+	return joint.other.Velocity.Sub(*joint.body.Velocity)
+}
+
+// Changes the velocity of the other body by the impulse, and the velocity of the body by the opposite impulse
+func (joint *baseJoint) applyImpulse(impulse util.Vec2[float32]) {
+	invMass, otherInvMass := joint.invMasses()
+	*joint.body.Velocity = joint.body.Velocity.Sub(impulse.Multiply(invMass))
+	if joint.other != nil {
+		*joint.other.Velocity = joint.other.Velocity.Add(impulse.Multiply(otherInvMass))
+	}
+
+	joint.impulse = joint.impulse.Add(impulse)
+}
+
+// Moves the other anchor by the distance relative to the anchor, sharing the movement between the bodies by their masses.
+// The bodies are restricted by the colliders they would collide with on the way, so a joint cannot pull a body through a wall,
+// but are not walked along the ground, which is left to their own updates.
+func (joint *baseJoint) correct(dist util.Vec2[float32]) {
+	invMass, otherInvMass := joint.invMasses()
+	share := invMass / (invMass + otherInvMass)
+	bodyDist := dist.Multiply(-share)
+	joint.body.move(bodyDist.X, bodyDist.Y)
+	if joint.other != nil {
+		otherDist := dist.Multiply(1 - share)
+		joint.other.move(otherDist.X, otherDist.Y)
+	}
+}
+
+// Turns the velocity of the bodies relative to each other by as far as the direction between the anchors turned since
+// the last step, as it would have turned had they swung around each other rather than moving in straight lines,
+// so that solving the joint removes the velocity pulling the anchors apart without slowing down their swinging
+func (joint *baseJoint) turnVelocity(normal util.Vec2[float32]) {
+	if joint.normal == (util.Vec2[float32]{}) {
+		return
+	}
+
+	cos, sin := joint.normal.Dot(normal), joint.normal.Cross(normal)
+	speed := joint.relativeVelocity()
+	turned := util.Vec2[float32]{X: speed.X*cos - speed.Y*sin, Y: speed.X*sin + speed.Y*cos}
+
+	invMass, otherInvMass := joint.invMasses()
+	joint.applyImpulse(turned.Sub(speed).Divide(invMass + otherInvMass))
+}
+
+// Returns the distance between the anchors and the direction from the anchor to the other anchor,
+// which points down when the anchors are on top of each other
+func (joint *baseJoint) separation() (float32, util.Vec2[float32]) {
+	anchor, otherAnchor := joint.anchors()
+	dist := otherAnchor.Sub(anchor)
+	length := dist.Length()
+	if length == 0 {
+		return 0, util.Vec2[float32]{X: 0, Y: 1}
+	}
+
+	return length, dist.Divide(length)
+}
+
+// Keeps the anchors of its bodies between a minimum and maximum distance apart, like a rod when they are equal,
+// or a rope when the minimum is zero
+type DistanceJoint struct {
+	baseJoint
+
+	minLength float32
+	maxLength float32
+}
+
+// Creates a joint that keeps the anchors of the bodies at the length apart, like a rod.
+// The other body can be nil to join the body to the other anchor as a point in the world.
+func NewDistanceJoint(
+	body *RigidBody,
+	anchor util.Vec2[float32],
+	other *RigidBody,
+	otherAnchor util.Vec2[float32],
+	length float32,
+) *DistanceJoint {
+	joint := DistanceJoint{baseJoint: newBaseJoint(body, anchor, other, otherAnchor)}
+	joint.SetLimits(length, length)
+
+	return &joint
+}
+
+// Creates a joint that keeps the anchors of the bodies at most the length apart, like a rope or chain link,
+// which lets them move towards each other freely
+func NewRopeJoint(
+	body *RigidBody,
+	anchor util.Vec2[float32],
+	other *RigidBody,
+	otherAnchor util.Vec2[float32],
+	length float32,
+) *DistanceJoint {
+	joint := DistanceJoint{baseJoint: newBaseJoint(body, anchor, other, otherAnchor)}
+	joint.SetLimits(0, length)
+
+	return &joint
+}
+
+func (joint *DistanceJoint) Limits() (float32, float32) {
+	return joint.minLength, joint.maxLength
+}
+
+// Sets how close and how far apart the anchors are kept, such as for reeling a rope in
+func (joint *DistanceJoint) SetLimits(minLength float32, maxLength float32) {
+	if minLength < 0 || maxLength < minLength {
+		panic("the limits of a distance joint must be positive and in order")
+	}
+
+	joint.minLength, joint.maxLength = minLength, maxLength
+}
+
+func (joint *DistanceJoint) prepare(seconds float32) {
+	if length, normal := joint.separation(); length > joint.maxLength-jointTolerance || length < joint.minLength+jointTolerance {
+		joint.turnVelocity(normal)
+	}
+}
+
+func (joint *DistanceJoint) solve() {
+	length, normal := joint.separation()
+
+	var target float32
+	if length > joint.maxLength-jointTolerance {
+		target = joint.maxLength
+	} else if length < joint.minLength+jointTolerance {
+		target = joint.minLength
+	} else {
+		return
+	}
+
+	// stops the bodies moving further past the limit they are held at, before moving them back to it
+	speed := joint.relativeVelocity()
+	normalSpeed := speed.Dot(normal)
+	if (target == joint.maxLength && normalSpeed > 0) || (target == joint.minLength && normalSpeed < 0) {
+		invMass, otherInvMass := joint.invMasses()
+		joint.applyImpulse(normal.Multiply(-normalSpeed / (invMass + otherInvMass)))
+	}
+
+	joint.correct(normal.Multiply(target - length))
+}
+
+// Pulls the anchors of its bodies towards being the rest length apart with a force that grows with how far they are from it,
+// such as for a springy bridge or a bungee cord
+type SpringJoint struct {
+	baseJoint
+
+	restLength float32
+	stiffness  float32 // the force for every pixel the anchors are from the rest length
+	damping    float32 // the force for every pixel per second the anchors are moving apart or together, which settles the spring
+}
+
+// Creates a spring between the anchors of the bodies.
+// The other body can be nil to join the body to the other anchor as a point in the world.
+func NewSpringJoint(
+	body *RigidBody,
+	anchor util.Vec2[float32],
+	other *RigidBody,
+	otherAnchor util.Vec2[float32],
+	restLength float32,
+	stiffness float32,
+	damping float32,
+) *SpringJoint {
+	if restLength < 0 || stiffness < 0 || damping < 0 {
+		panic("the rest length, stiffness and damping of a spring cannot be negative")
+	}
+
+	return &SpringJoint{
+		baseJoint:  newBaseJoint(body, anchor, other, otherAnchor),
+		restLength: restLength,
+		stiffness:  stiffness,
+		damping:    damping,
+	}
+}
+
+func (joint *SpringJoint) RestLength() float32 {
+	return joint.restLength
+}
+
+func (joint *SpringJoint) SetRestLength(restLength float32) {
+	if restLength < 0 {
+		panic("the rest length of a spring cannot be negative")
+	}
+
+	joint.restLength = restLength
+}
+
+// The force of a spring does not depend on the other joints, so it is applied once every step rather than being solved
+func (joint *SpringJoint) prepare(seconds float32) {
+	length, normal := joint.separation()
+	speed := joint.relativeVelocity()
+
+	force := joint.stiffness*(length-joint.restLength) + joint.damping*speed.Dot(normal)
+	joint.applyImpulse(normal.Multiply(-force * seconds))
+}
+
+func (joint *SpringJoint) solve() {}
+
+// Holds the other body at a fixed distance from the anchor of the body, swinging around it like a door on a hinge
+// between the angle limits. Bodies do not rotate, so the angle is of the direction from the anchor to the other anchor,
+// in radians clockwise from the right as y points down. Equal limits hold the other body in place, such as for
+// attaching an object to a body.
+type HingeJoint struct {
+	baseJoint
+
+	length     float32
+	lowerAngle float32
+	upperAngle float32
+}
+
+// Creates a hinge at the current distance between the anchors, which swings freely when the limits are a full turn apart.
+// The other body can be nil to hinge the body around the other anchor as a point in the world.
+func NewHingeJoint(
+	body *RigidBody,
+	anchor util.Vec2[float32],
+	other *RigidBody,
+	otherAnchor util.Vec2[float32],
+	lowerAngle float32,
+	upperAngle float32,
+) *HingeJoint {
+	joint := HingeJoint{baseJoint: newBaseJoint(body, anchor, other, otherAnchor)}
+	joint.length, _ = joint.separation()
+	joint.SetLimits(lowerAngle, upperAngle)
+
+	return &joint
+}
+
+func (joint *HingeJoint) Limits() (float32, float32) {
+	return joint.lowerAngle, joint.upperAngle
+}
+
+func (joint *HingeJoint) SetLimits(lowerAngle float32, upperAngle float32) {
+	if upperAngle < lowerAngle {
+		panic("the lower angle of a hinge cannot be above its upper angle")
+	}
+
+	joint.lowerAngle, joint.upperAngle = lowerAngle, upperAngle
+}
+
+// Returns the angle of the direction from the anchor to the other anchor
+func (joint *HingeJoint) Angle() float32 {
+	_, normal := joint.separation()
+	return float32(math.Atan2(float64(normal.Y), float64(normal.X)))
+}
+
+func (joint *HingeJoint) prepare(seconds float32) {
+	_, normal := joint.separation()
+	joint.turnVelocity(normal)
+}
+
+func (joint *HingeJoint) solve() {
+	length, normal := joint.separation()
+	speed := joint.relativeVelocity()
+	invMass, otherInvMass := joint.invMasses()
+
+	// keeps the anchors the length apart, like a rod
+	normalSpeed := speed.Dot(normal)
+	joint.applyImpulse(normal.Multiply(-normalSpeed / (invMass + otherInvMass)))
+	speed = speed.Sub(normal.Multiply(normalSpeed))
+
+	angle := joint.Angle()
+	target := angle
+	if joint.upperAngle-joint.lowerAngle < 2*math.Pi {
+		// measured from the middle of the limits so that the limits can wrap around a full turn
+		middle := (joint.lowerAngle + joint.upperAngle) / 2
+		offset := float32(math.Remainder(float64(angle-middle), 2*math.Pi))
+		halfRange := (joint.upperAngle - joint.lowerAngle) / 2
+		target = angle + min(max(offset, -halfRange), halfRange) - offset
+
+		// the angle grows as the other anchor moves along the tangent, so that movement is stopped at the upper limit
+		tangent := util.Vec2[float32]{X: -normal.Y, Y: normal.X}
+		tangentSpeed := speed.Dot(tangent)
+		if (offset >= halfRange-jointTolerance && tangentSpeed > 0) || (offset <= -halfRange+jointTolerance && tangentSpeed < 0) {
+			joint.applyImpulse(tangent.Multiply(-tangentSpeed / (invMass + otherInvMass)))
+		}
+	}
+
+	if target == angle && math.Abs(float64(length-joint.length)) <= jointTolerance {
+		return
+	}
+
+	sin, cos := math.Sincos(float64(target))
+	targetDist := util.Vec2[float32]{X: float32(cos), Y: float32(sin)}.Multiply(joint.length)
+	joint.correct(targetDist.Sub(normal.Multiply(length)))
+}
@@ -0,0 +1,321 @@
+package objs
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/TheRaizer/GolangGame/core"
+	"github.com/TheRaizer/GolangGame/core/collision"
+	"github.com/TheRaizer/GolangGame/util"
+	"github.com/TheRaizer/GolangGame/util/datastructures/quadtree"
+	"github.com/stretchr/testify/require"
+)
+
+const jointGravity float32 = 1000
+
+type jointScene struct {
+	collisionSys *collision.CollisionSystem
+	world        *PhysicsWorld
+}
+
+func newJointScene(gravity float32) jointScene {
+	collisionSys := collision.NewCollisionSystem(quadtree.Rect{X: -500, Y: -500, W: 1000, H: 1000})
+	return jointScene{&collisionSys, &PhysicsWorld{Gravity: util.Vec2[float32]{X: 0, Y: gravity}}}
+}
+
+// creates a 10x10 body centred on the point
+func (scene jointScene) newBodyCentredOn(name string, centre util.Vec2[float32]) *RigidBody {
+	pos := util.Vec2[float32]{X: centre.X - 5, Y: centre.Y - 5}
+	rb, _ := newRigidBodyInScene(core.PLAYER_LAYER, name, pos, util.Vec2[float32]{}, scene.collisionSys, scene.world, false)
+	return rb
+}
+
+// steps the bodies and then the joints like the game does, for the seconds, calling the check after every step
+func simulate(bodies []*RigidBody, jointSys *JointSystem, seconds float32, check func()) {
+	const dt uint64 = 16
+	for range int(seconds * 1000 / float32(dt)) {
+		for _, rb := range bodies {
+			rb.OnUpdate(dt, nil)
+		}
+		jointSys.Step(dt)
+		check()
+	}
+}
+
+// the kinetic energy of the bodies, and their potential energy under the gravity pulling them down
+func energyOf(bodies []*RigidBody, gravity float32) float32 {
+	var energy float32
+	for _, rb := range bodies {
+		centre := rb.collider.Rect.Center()
+		energy += rb.mass*rb.Velocity.LengthSquared()/2 - rb.mass*gravity*centre.Y
+	}
+
+	return energy
+}
+
+func TestJointsShouldKeepTheEnergyOfAPendulum(t *testing.T) {
+	const NAME string = "should keep swinging a pendulum held by a %s"
+	getName := func(input string) string {
+		return fmt.Sprintf(NAME, input)
+	}
+
+	cases := []util.TestCase[string, bool]{
+		{Name: getName, Input: "rod"},
+		{Name: getName, Input: "rope"},
+		{Name: getName, Input: "hinge"},
+	}
+
+	util.IterateTestCases(cases, t, func(testCase util.TestCase[string, bool]) {
+		scene := newJointScene(jointGravity)
+		jointSys := NewJointSystem()
+
+		// released level with the pivot
+		rb := scene.newBodyCentredOn("bob", util.Vec2[float32]{X: 100, Y: 0})
+		var joint Joint
+		switch testCase.Input {
+		case "rod":
+			joint = NewDistanceJoint(rb, util.Vec2[float32]{}, nil, util.Vec2[float32]{}, 100)
+		case "rope":
+			joint = NewRopeJoint(rb, util.Vec2[float32]{}, nil, util.Vec2[float32]{}, 100)
+		case "hinge":
+			joint = NewHingeJoint(rb, util.Vec2[float32]{}, nil, util.Vec2[float32]{}, 0, 2*math.Pi)
+		}
+		jointSys.AddJoint(joint)
+
+		bodies := []*RigidBody{rb}
+		initial := energyOf(bodies, jointGravity)
+		// the energy the pendulum gains by swinging down to the bottom of its swing
+		swing := rb.mass * jointGravity * 100
+
+		lowest := float32(0)
+		simulate(bodies, &jointSys, 10, func() {
+			length, _ := joint.base().separation()
+			require.InDelta(t, 100, length, 0.01)
+			require.InDelta(t, initial, energyOf(bodies, jointGravity), float64(swing*0.05))
+
+			lowest = max(lowest, rb.collider.Rect.Center().Y)
+		})
+
+		// still swinging all the way down
+		require.InDelta(t, 100, lowest, 1)
+	})
+}
+
+func TestSpringJointShouldOscillate(t *testing.T) {
+	type TestInput struct {
+		stiffness float32
+		damping   float32
+	}
+
+	const NAME string = "should keep the energy of a spring that is not damped with %+v"
+	getName := func(input TestInput) string {
+		return fmt.Sprintf(NAME, input)
+	}
+
+	cases := []util.TestCase[TestInput, bool]{
+		{Name: getName, Input: TestInput{stiffness: 50, damping: 0}, Expected: true},
+		{Name: getName, Input: TestInput{stiffness: 100, damping: 0}, Expected: true},
+		{Name: getName, Input: TestInput{stiffness: 50, damping: 5}, Expected: false},
+	}
+
+	util.IterateTestCases(cases, t, func(testCase util.TestCase[TestInput, bool]) {
+		scene := newJointScene(0)
+		jointSys := NewJointSystem()
+
+		// stretched 20 pixels past its rest length
+		rb := scene.newBodyCentredOn("bob", util.Vec2[float32]{X: 120, Y: 0})
+		spring := NewSpringJoint(rb, util.Vec2[float32]{}, nil, util.Vec2[float32]{}, 100, testCase.Input.stiffness, testCase.Input.damping)
+		jointSys.AddJoint(spring)
+
+		energy := func() float32 {
+			length, _ := spring.separation()
+			stretch := length - spring.RestLength()
+			return rb.mass*rb.Velocity.X*rb.Velocity.X/2 + testCase.Input.stiffness*stretch*stretch/2
+		}
+		initial := energy()
+
+		simulate([]*RigidBody{rb}, &jointSys, 10, func() {
+			require.Less(t, energy(), initial*1.1)
+			require.Zero(t, rb.Velocity.Y)
+		})
+
+		if testCase.Expected {
+			require.Greater(t, energy(), initial*0.9)
+		} else {
+			require.Less(t, energy(), initial*0.01)
+		}
+	})
+}
+
+func TestRopeJointsShouldHoldAChain(t *testing.T) {
+	scene := newJointScene(jointGravity)
+	jointSys := NewJointSystem()
+
+	// links released in a line level with the pivot, the first of which hangs from it
+	var bodies []*RigidBody
+	for i := range 5 {
+		rb := scene.newBodyCentredOn(fmt.Sprintf("link_%d", i), util.Vec2[float32]{X: float32(i+1) * 20, Y: 0})
+		if i == 0 {
+			jointSys.AddJoint(NewRopeJoint(rb, util.Vec2[float32]{}, nil, util.Vec2[float32]{}, 20))
+		} else {
+			jointSys.AddJoint(NewRopeJoint(bodies[i-1], util.Vec2[float32]{}, rb, util.Vec2[float32]{}, 20))
+		}
+		bodies = append(bodies, rb)
+	}
+
+	initial := energyOf(bodies, jointGravity)
+	simulate(bodies, &jointSys, 10, func() {
+		for _, joint := range jointSys.Joints() {
+			length, _ := joint.base().separation()
+			// the links can stretch a little while the chain whips around, as solving one link pulls on the next
+			require.LessOrEqual(t, length, float32(20.5))
+		}
+
+		// the chain can lose energy to its links snapping taut, but never gains it
+		require.LessOrEqual(t, energyOf(bodies, jointGravity), initial+1)
+	})
+
+	// the end of the chain is left no further from the pivot than the chain is long
+	end := bodies[4].collider.Rect.Center()
+	require.LessOrEqual(t, end.Length(), float32(100.5))
+}
+
+func TestHingeJointShouldSwingWithinItsLimits(t *testing.T) {
+	type TestInput struct {
+		lowerAngle float32
+		upperAngle float32
+	}
+
+	const NAME string = "should rest at the angle of %v with %+v"
+	getName := func(input TestInput) string {
+		return fmt.Sprintf(NAME, input.upperAngle, input)
+	}
+
+	cases := []util.TestCase[TestInput, float32]{
+		// falls until stopped at the upper limit
+		{Name: getName, Input: TestInput{0, math.Pi / 4}, Expected: math.Pi / 4},
+		// a limit reaching round past the angle of pi
+		{Name: getName, Input: TestInput{-math.Pi, math.Pi / 4}, Expected: math.Pi / 4},
+		// held in place
+		{Name: getName, Input: TestInput{0, 0}, Expected: 0},
+		// swings freely and settles hanging down
+		{Name: getName, Input: TestInput{0, 2 * math.Pi}, Expected: math.Pi / 2},
+	}
+
+	util.IterateTestCases(cases, t, func(testCase util.TestCase[TestInput, float32]) {
+		scene := newJointScene(jointGravity)
+		jointSys := NewJointSystem()
+
+		body := scene.newBodyCentredOn("body", util.Vec2[float32]{X: 0, Y: 0})
+		body.SetGravityScale(0)
+		body.SetMass(1000)
+		// the arm starts out to the right of the body
+		arm := scene.newBodyCentredOn("arm", util.Vec2[float32]{X: 50, Y: 0})
+		arm.SetDrag(1)
+		hinge := NewHingeJoint(body, util.Vec2[float32]{}, arm, util.Vec2[float32]{X: -20, Y: 0}, testCase.Input.lowerAngle, testCase.Input.upperAngle)
+		jointSys.AddJoint(hinge)
+
+		simulate([]*RigidBody{body, arm}, &jointSys, 20, func() {
+			if testCase.Input.upperAngle-testCase.Input.lowerAngle < 2*math.Pi {
+				require.LessOrEqual(t, hinge.Angle(), testCase.Input.upperAngle+0.01)
+			}
+		})
+
+		length, _ := hinge.separation()
+		require.InDelta(t, 30, length, 0.01)
+		require.InDelta(t, testCase.Expected, hinge.Angle(), 0.01)
+	})
+}
+
+func TestJointsShouldLeaveTheContactStateToTheBodies(t *testing.T) {
+	scene := newJointScene(jointGravity)
+	jointSys := NewJointSystem()
+	collision.NewCollider(core.WALL_LAYER, "floor", quadtree.Rect{X: -100, Y: 10, W: 200, H: 10}, scene.collisionSys, scene.collisionSys, nil, nil)
+
+	rb := scene.newBodyCentredOn("bob", util.Vec2[float32]{X: 0, Y: 5})
+	rb.OnUpdate(16, nil)
+	require.True(t, rb.IsGrounded())
+
+	// a rod shorter than the distance to its point lifts the body off of the floor
+	jointSys.AddJoint(NewDistanceJoint(rb, util.Vec2[float32]{}, nil, util.Vec2[float32]{X: 0, Y: -20}, 20))
+	jointSys.Step(16)
+
+	require.InDelta(t, 0, rb.collider.Rect.Center().Y, 0.01)
+	require.True(t, rb.IsGrounded())
+	require.Equal(t, util.Vec2[float32]{X: 0, Y: 1}, rb.GroundNormal())
+
+	rb.OnUpdate(16, nil)
+	require.False(t, rb.IsGrounded())
+}
+
+func TestJointSystemShouldBreakJoints(t *testing.T) {
+	type TestInput struct {
+		breakForce float32
+		mass       float32
+	}
+
+	// a rope holding a body up holds it with its weight
+	const NAME string = "should break a rope holding a body up with %+v: %v"
+	getName := func(input TestInput) string {
+		return fmt.Sprintf(NAME, input, input.breakForce < input.mass*jointGravity)
+	}
+
+	cases := []util.TestCase[TestInput, bool]{
+		{Name: getName, Input: TestInput{breakForce: 900, mass: 1}, Expected: true},
+		{Name: getName, Input: TestInput{breakForce: 1100, mass: 1}, Expected: false},
+		{Name: getName, Input: TestInput{breakForce: 1900, mass: 2}, Expected: true},
+		{Name: getName, Input: TestInput{breakForce: 2100, mass: 2}, Expected: false},
+	}
+
+	util.IterateTestCases(cases, t, func(testCase util.TestCase[TestInput, bool]) {
+		scene := newJointScene(jointGravity)
+		jointSys := NewJointSystem()
+
+		rb := scene.newBodyCentredOn("bob", util.Vec2[float32]{X: 0, Y: 100})
+		rb.SetMass(testCase.Input.mass)
+		rope := NewRopeJoint(rb, util.Vec2[float32]{}, nil, util.Vec2[float32]{}, 100)
+		rope.SetBreakForce(testCase.Input.breakForce)
+
+		breaks := 0
+		rope.AddBreakEvent(func() {
+			breaks++
+		})
+		jointSys.AddJoint(rope)
+
+		simulate([]*RigidBody{rb}, &jointSys, 1, func() {})
+
+		require.Equal(t, testCase.Expected, rope.IsBroken())
+		if testCase.Expected {
+			require.Equal(t, 1, breaks)
+			require.Empty(t, jointSys.Joints())
+			// falls once the rope breaks
+			require.Greater(t, rb.collider.Rect.Center().Y, float32(200))
+		} else {
+			require.Zero(t, breaks)
+			require.Len(t, jointSys.Joints(), 1)
+			require.InDelta(t, 100, rb.collider.Rect.Center().Y, 0.1)
+		}
+	})
+}
+
+func TestJointsShouldPanic(t *testing.T) {
+	scene := newJointScene(0)
+	rb := scene.newBodyCentredOn("bob", util.Vec2[float32]{})
+
+	require.PanicsWithValue(t, "a joint needs a rigid body to constrain", func() {
+		NewRopeJoint(nil, util.Vec2[float32]{}, rb, util.Vec2[float32]{}, 10)
+	})
+	require.PanicsWithValue(t, "the break force of a joint must be positive", func() {
+		NewRopeJoint(rb, util.Vec2[float32]{}, nil, util.Vec2[float32]{}, 10).SetBreakForce(0)
+	})
+	require.PanicsWithValue(t, "the limits of a distance joint must be positive and in order", func() {
+		NewRopeJoint(rb, util.Vec2[float32]{}, nil, util.Vec2[float32]{}, 10).SetLimits(5, 4)
+	})
+	require.PanicsWithValue(t, "the rest length, stiffness and damping of a spring cannot be negative", func() {
+		NewSpringJoint(rb, util.Vec2[float32]{}, nil, util.Vec2[float32]{}, 10, -1, 0)
+	})
+	require.PanicsWithValue(t, "the lower angle of a hinge cannot be above its upper angle", func() {
+		NewHingeJoint(rb, util.Vec2[float32]{}, nil, util.Vec2[float32]{X: 10}, 1, 0)
+	})
+}
@@ -0,0 +1,76 @@
+package objs
+
+import "github.com/TheRaizer/GolangGame/util"
+
+// how many times the joints are solved in a step, as solving one joint can pull its bodies away from satisfying
+// the joints solved before it, such as along a chain
+const jointIterations = 8
+
+// Solves the joints between rigid bodies together, after the bodies have moved
+type JointSystem struct {
+	joints []Joint
+}
+
+func NewJointSystem() JointSystem {
+	return JointSystem{}
+}
+
+func (jointSys *JointSystem) AddJoint(joint Joint) {
+	jointSys.joints = append(jointSys.joints, joint)
+}
+
+func (jointSys *JointSystem) RemoveJoint(joint Joint) {
+	for i, other := range jointSys.joints {
+		if other == joint {
+			jointSys.joints = append(jointSys.joints[:i], jointSys.joints[i+1:]...)
+			return
+		}
+	}
+}
+
+func (jointSys *JointSystem) Joints() []Joint {
+	return jointSys.joints
+}
+
+// Solves every joint iteratively, breaking the joints that had to hold their bodies with more than their break force,
+// and removes the broken joints
+func (jointSys *JointSystem) Step(dt uint64) {
+	seconds := float32(dt) / 1000
+	if seconds == 0 {
+		return
+	}
+
+	jointSys.removeBroken()
+	for _, joint := range jointSys.joints {
+		joint.base().impulse = util.Vec2[float32]{}
+		joint.prepare(seconds)
+	}
+
+	for range jointIterations {
+		for _, joint := range jointSys.joints {
+			joint.solve()
+		}
+	}
+
+	for _, joint := range jointSys.joints {
+		base := joint.base()
+		_, base.normal = base.separation()
+		// summed as vectors, so the impulses of iterations that cancel each other out do not add to the force
+		if base.impulse.Length()/seconds > base.breakForce {
+			joint.Break()
+		}
+	}
+	jointSys.removeBroken()
+}
+
+func (jointSys *JointSystem) removeBroken() {
+	kept := jointSys.joints[:0]
+	for _, joint := range jointSys.joints {
+		if !joint.IsBroken() {
+			kept = append(kept, joint)
+		}
+	}
+
+	clear(jointSys.joints[len(kept):])
+	jointSys.joints = kept
+}
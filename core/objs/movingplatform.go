@@ -0,0 +1,224 @@
+package objs
+
+import (
+	"math"
+
+	"github.com/TheRaizer/GolangGame/core"
+	"github.com/TheRaizer/GolangGame/core/collision"
+	"github.com/TheRaizer/GolangGame/util"
+	"github.com/TheRaizer/GolangGame/util/datastructures/quadtree"
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// Maps how far along a segment of a path the time is, from 0 to 1, to how far along it the platform is, from 0 to 1
+type Easing func(t float32) float32
+
+func EaseLinear(t float32) float32 {
+	return t
+}
+
+// Speeds up from the start of the segment and slows down in to its end
+func EaseInOutSine(t float32) float32 {
+	return float32(1-math.Cos(math.Pi*float64(t))) / 2
+}
+
+func EaseInOutQuad(t float32) float32 {
+	if t < 0.5 {
+		return 2 * t * t
+	}
+
+	return 1 - 2*(1-t)*(1-t)
+}
+
+// A kinematic platform that moves its collider along waypoints regardless of what it collides with. It carries the rigid bodies
+// standing on top of it along with it, and pushes the rigid bodies it runs in to out of its way, which are crushed in place
+// when there is nowhere for them to go.
+type MovingPlatform struct {
+	core.BaseGameObject
+
+	waypoints    []util.Vec2[float32] // the positions the platform moves between, which it starts at the first of
+	travelTime   uint64               // the milliseconds taken to move between two waypoints
+	waitTime     uint64               // the milliseconds waited at every waypoint
+	easing       Easing
+	isLooping    bool // whether the platform moves from the last waypoint back to the first, rather than back along the waypoints
+	from         int
+	to           int
+	direction    int    // 1 when moving forwards along the waypoints, and -1 when moving back along them
+	elapsed      uint64 // the milliseconds spent moving from the current waypoint to the next
+	waiting      uint64 // the milliseconds left to wait at the current waypoint
+	velocity     util.Vec2[float32]
+	collider     *collision.Collider
+	collisionSys collision.CollisionSystemMediator
+	riders       []*RigidBody          // reused between updates so that finding riders does not allocate
+	overlapBuf   []*collision.Collider // reused for the colliders found standing on or overlapping the platform
+	queryIgnore  []*collision.Collider // the collider of the platform, which its queries ignore
+}
+
+// Creates a platform at the first waypoint that moves its collider, which it adds as its child, between the waypoints.
+// The waypoints are copied, and a nil easing moves the platform at a constant speed like EaseLinear.
+func NewMovingPlatform(
+	layer int,
+	name string,
+	waypoints []util.Vec2[float32],
+	travelTime uint64,
+	easing Easing,
+	gameObjectStore core.GameObjectStore,
+	collider *collision.Collider,
+	collisionSys collision.CollisionSystemMediator,
+) *MovingPlatform {
+	if len(waypoints) < 2 {
+		panic("a moving platform needs at least two waypoints")
+	}
+	if travelTime == 0 {
+		panic("the travel time of a moving platform must be positive")
+	}
+	if easing == nil {
+		easing = EaseLinear
+	}
+
+	platform := MovingPlatform{
+		BaseGameObject: core.NewBaseGameObject(layer, name, waypoints[0], gameObjectStore),
+		waypoints:      append([]util.Vec2[float32](nil), waypoints...),
+		travelTime:     travelTime,
+		easing:         easing,
+		to:             1,
+		direction:      1,
+		collider:       collider,
+		collisionSys:   collisionSys,
+		queryIgnore:    []*collision.Collider{collider},
+	}
+	platform.AddChild(collider)
+	collider.SetBody(&platform)
+
+	return &platform
+}
+
+// Waits at every waypoint for the milliseconds before moving on to the next one
+func (platform *MovingPlatform) SetWaitTime(waitTime uint64) {
+	platform.waitTime = waitTime
+}
+
+// Moves the platform from the last waypoint straight back to the first, such as around a loop,
+// rather than back along the waypoints
+func (platform *MovingPlatform) SetLooping(isLooping bool) {
+	platform.isLooping = isLooping
+}
+
+// Returns how fast the platform moved in the last update, in pixels per second
+func (platform *MovingPlatform) Velocity() util.Vec2[float32] {
+	return platform.velocity
+}
+
+func (platform *MovingPlatform) OnUpdate(dt uint64, surface *sdl.Surface) {
+	if dt == 0 {
+		return
+	}
+
+	dist := platform.advance(dt).Sub(platform.Pos)
+	platform.velocity = dist.Multiply(1000).Divide(float32(dt))
+	if dist == (util.Vec2[float32]{}) {
+		return
+	}
+
+	// found before moving, as moving down leaves the riders above the platform, and moving up lifts the platform in to them
+	platform.findRiders()
+	platform.UpdatePos(dist.X, dist.Y)
+
+	for _, rider := range platform.riders {
+		rider.detectCollision(dist.X, dist.Y)
+	}
+
+	platform.pushBodies(dist)
+}
+
+// Moves along the waypoints for the milliseconds, and returns where the platform is after them
+func (platform *MovingPlatform) advance(dt uint64) util.Vec2[float32] {
+	for dt > 0 {
+		if platform.waiting > 0 {
+			waited := min(platform.waiting, dt)
+			platform.waiting -= waited
+			dt -= waited
+			continue
+		}
+
+		moved := min(platform.travelTime-platform.elapsed, dt)
+		platform.elapsed += moved
+		dt -= moved
+
+		if platform.elapsed == platform.travelTime {
+			platform.elapsed = 0
+			platform.waiting = platform.waitTime
+			platform.from = platform.to
+			platform.to = platform.next()
+		}
+	}
+
+	from, to := platform.waypoints[platform.from], platform.waypoints[platform.to]
+	progress := platform.easing(float32(platform.elapsed) / float32(platform.travelTime))
+
+	return from.Add(to.Sub(from).Multiply(progress))
+}
+
+// Returns the waypoint after the one the platform has reached, turning back at either end of the waypoints unless looping
+func (platform *MovingPlatform) next() int {
+	if platform.isLooping {
+		return (platform.from + 1) % len(platform.waypoints)
+	}
+
+	next := platform.from + platform.direction
+	if next < 0 || next >= len(platform.waypoints) {
+		platform.direction = -platform.direction
+		next = platform.from + platform.direction
+	}
+
+	return next
+}
+
+// Finds the rigid bodies standing on top of the platform
+func (platform *MovingPlatform) findRiders() {
+	rect := platform.collider.Rect
+	top := quadtree.Rect{X: rect.X, Y: rect.Y - contactTolerance, W: rect.W, H: contactTolerance * 2}
+
+	platform.riders = platform.riders[:0]
+	platform.overlapBuf = platform.collisionSys.OverlapBox(top, platform.queryFilter(), platform.overlapBuf[:0])
+	for _, other := range platform.overlapBuf {
+		rb, ok := other.Body().(*RigidBody)
+		if ok && rb.IsGrounded() && other.Rect.Bottom() <= rect.Y+contactTolerance {
+			platform.riders = append(platform.riders, rb)
+		}
+	}
+}
+
+// Pushes the rigid bodies the platform moved in to out of its way, along the shortest way out of it, by at most as far as
+// the platform moved that way. One-way platforms let the bodies they move up in to pass through them instead.
+func (platform *MovingPlatform) pushBodies(dist util.Vec2[float32]) {
+	if platform.collider.IsOneWay() {
+		return
+	}
+
+	platform.overlapBuf = platform.collisionSys.OverlapBox(platform.collider.Rect, platform.queryFilter(), platform.overlapBuf[:0])
+	for _, other := range platform.overlapBuf {
+		rb, ok := other.Body().(*RigidBody)
+		if !ok {
+			continue
+		}
+
+		// only the colliders behind the body stop it, so a body with nowhere to go is left overlapping the platform
+		// rather than being pushed out of another side of it, such as through the floor
+		contact := platform.collider.ContactWith(other)
+		push := min(contact.Depth, dist.Dot(contact.Normal))
+		if push > 0 {
+			pushed := contact.Normal.Multiply(push)
+			rb.detectCollision(pushed.X, pushed.Y)
+		}
+	}
+}
+
+// Returns the filter for the colliders that the platform collides with, other than its own collider
+func (platform *MovingPlatform) queryFilter() collision.QueryFilter {
+	return collision.QueryFilter{
+		Mask:           platform.collisionSys.Layers().Mask(platform.collider.Layer(), core.COLLIDE),
+		Ignore:         platform.queryIgnore,
+		IgnoreTriggers: true,
+	}
+}
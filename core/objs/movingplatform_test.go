@@ -0,0 +1,201 @@
+package objs
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/TheRaizer/GolangGame/core"
+	"github.com/TheRaizer/GolangGame/core/collision"
+	"github.com/TheRaizer/GolangGame/util"
+	"github.com/TheRaizer/GolangGame/util/datastructures/quadtree"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// creates a 50x10 platform moving between the waypoints
+func newPlatformInScene(
+	waypoints []util.Vec2[float32],
+	travelTime uint64,
+	easing Easing,
+	collisionSys *collision.CollisionSystem,
+) *MovingPlatform {
+	store := MockGameObjectStore{}
+	store.On("AddGameObject", mock.Anything)
+	collider := collision.NewCollider(core.PLATFORM_LAYER, "platform_collider", quadtree.Rect{X: 0, Y: 0, W: 50, H: 10}, collisionSys, collisionSys, nil, &store)
+
+	return NewMovingPlatform(core.PLATFORM_LAYER, "platform", waypoints, travelTime, easing, &store, collider, collisionSys)
+}
+
+func TestMovingPlatformShouldFollowItsWaypoints(t *testing.T) {
+	type TestInput struct {
+		waypoints []util.Vec2[float32]
+		easing    string
+		waitTime  uint64
+		isLooping bool
+		elapsed   uint64
+	}
+
+	const NAME string = "should be at %+v with %+v"
+	getName := func(input TestInput) string {
+		return fmt.Sprintf(NAME, input.elapsed, input)
+	}
+
+	line := []util.Vec2[float32]{{X: 0, Y: 0}, {X: 100, Y: 0}}
+	corner := []util.Vec2[float32]{{X: 0, Y: 0}, {X: 100, Y: 0}, {X: 100, Y: 100}}
+
+	cases := []util.TestCase[TestInput, util.Vec2[float32]]{
+		{Name: getName, Input: TestInput{line, "linear", 0, false, 500}, Expected: util.Vec2[float32]{X: 50, Y: 0}},
+		{Name: getName, Input: TestInput{line, "sine", 0, false, 250}, Expected: util.Vec2[float32]{X: 14.6447, Y: 0}},
+		{Name: getName, Input: TestInput{line, "quad", 0, false, 750}, Expected: util.Vec2[float32]{X: 87.5, Y: 0}},
+		// turning back at the end of the waypoints
+		{Name: getName, Input: TestInput{line, "linear", 0, false, 1250}, Expected: util.Vec2[float32]{X: 75, Y: 0}},
+		{Name: getName, Input: TestInput{corner, "linear", 0, false, 2500}, Expected: util.Vec2[float32]{X: 100, Y: 50}},
+		{Name: getName, Input: TestInput{corner, "linear", 0, false, 4000}, Expected: util.Vec2[float32]{X: 0, Y: 0}},
+		// looping back to the first waypoint
+		{Name: getName, Input: TestInput{corner, "linear", 0, true, 2500}, Expected: util.Vec2[float32]{X: 50, Y: 50}},
+		{Name: getName, Input: TestInput{corner, "linear", 0, true, 3500}, Expected: util.Vec2[float32]{X: 50, Y: 0}},
+		// waiting at every waypoint
+		{Name: getName, Input: TestInput{line, "linear", 500, false, 1250}, Expected: util.Vec2[float32]{X: 100, Y: 0}},
+		{Name: getName, Input: TestInput{line, "linear", 500, false, 2000}, Expected: util.Vec2[float32]{X: 50, Y: 0}},
+	}
+
+	easings := map[string]Easing{"linear": EaseLinear, "sine": EaseInOutSine, "quad": EaseInOutQuad}
+
+	util.IterateTestCases(cases, t, func(testCase util.TestCase[TestInput, util.Vec2[float32]]) {
+		collisionSys := collision.NewCollisionSystem(quadtree.Rect{X: -500, Y: -500, W: 1000, H: 1000})
+		platform := newPlatformInScene(testCase.Input.waypoints, 1000, easings[testCase.Input.easing], &collisionSys)
+		platform.SetWaitTime(testCase.Input.waitTime)
+		platform.SetLooping(testCase.Input.isLooping)
+
+		for range testCase.Input.elapsed / 50 {
+			platform.OnUpdate(50, nil)
+		}
+
+		require.InDelta(t, testCase.Expected.X, platform.Pos.X, 1e-3)
+		require.InDelta(t, testCase.Expected.Y, platform.Pos.Y, 1e-3)
+		require.InDelta(t, testCase.Expected.X, platform.collider.Rect.X, 1e-3)
+		require.InDelta(t, testCase.Expected.Y, platform.collider.Rect.Y, 1e-3)
+	})
+}
+
+func TestMovingPlatformShouldCarryItsRiders(t *testing.T) {
+	type TestInput struct {
+		to                 util.Vec2[float32]
+		isPlatformUpdated1 bool // whether the platform is updated before the rider, as the game updates in any order
+	}
+
+	const NAME string = "should carry the rider with %+v"
+	getName := func(input TestInput) string {
+		return fmt.Sprintf(NAME, input)
+	}
+
+	cases := []util.TestCase[TestInput, bool]{
+		{Name: getName, Input: TestInput{util.Vec2[float32]{X: 100, Y: 100}, true}},
+		{Name: getName, Input: TestInput{util.Vec2[float32]{X: 100, Y: 100}, false}},
+		{Name: getName, Input: TestInput{util.Vec2[float32]{X: -100, Y: 100}, true}},
+		{Name: getName, Input: TestInput{util.Vec2[float32]{X: 0, Y: 0}, true}},
+		{Name: getName, Input: TestInput{util.Vec2[float32]{X: 0, Y: 0}, false}},
+		{Name: getName, Input: TestInput{util.Vec2[float32]{X: 0, Y: 200}, true}},
+		{Name: getName, Input: TestInput{util.Vec2[float32]{X: 0, Y: 200}, false}},
+	}
+
+	util.IterateTestCases(cases, t, func(testCase util.TestCase[TestInput, bool]) {
+		collisionSys := collision.NewCollisionSystem(quadtree.Rect{X: -500, Y: -500, W: 1000, H: 1000})
+		world := PhysicsWorld{Gravity: util.Vec2[float32]{X: 0, Y: 1000}}
+
+		waypoints := []util.Vec2[float32]{{X: 0, Y: 100}, testCase.Input.to}
+		platform := newPlatformInScene(waypoints, 1000, EaseInOutSine, &collisionSys)
+		rb, rider := newRigidBodyInScene(core.PLAYER_LAYER, "rider", util.Vec2[float32]{X: 20, Y: 90}, util.Vec2[float32]{}, &collisionSys, &world, false)
+
+		// lands on the platform
+		rb.OnUpdate(16, nil)
+		require.True(t, rb.IsGrounded())
+
+		for range 1000 / 16 {
+			if testCase.Input.isPlatformUpdated1 {
+				platform.OnUpdate(16, nil)
+				rb.OnUpdate(16, nil)
+			} else {
+				rb.OnUpdate(16, nil)
+				platform.OnUpdate(16, nil)
+			}
+
+			// standing still on top of the platform
+			require.InDelta(t, platform.Pos.X+20, rider.Pos.X, 0.1)
+			require.InDelta(t, platform.Pos.Y-10, rider.Pos.Y, 0.1)
+		}
+	})
+}
+
+func TestMovingPlatformShouldPushBodies(t *testing.T) {
+	type TestInput struct {
+		to       util.Vec2[float32]
+		isOneWay bool
+		isWalled bool // whether there is a wall behind the body
+	}
+
+	const NAME string = "should leave the body at %+v with %+v"
+	getName := func(input TestInput) string {
+		return fmt.Sprintf(NAME, input.to, input)
+	}
+
+	cases := []util.TestCase[TestInput, util.Vec2[float32]]{
+		// pushed along the floor by the right side of the platform
+		{Name: getName, Input: TestInput{util.Vec2[float32]{X: 50, Y: 90}, false, false}, Expected: util.Vec2[float32]{X: 100, Y: 90}},
+		// crushed against the wall, rather than pushed through it
+		{Name: getName, Input: TestInput{util.Vec2[float32]{X: 50, Y: 90}, false, true}, Expected: util.Vec2[float32]{X: 80, Y: 90}},
+		// lifted by a platform rising from under it
+		{Name: getName, Input: TestInput{util.Vec2[float32]{X: 55, Y: 50}, false, false}, Expected: util.Vec2[float32]{X: 80, Y: 40}},
+		// passed through by a one-way platform rising from under it
+		{Name: getName, Input: TestInput{util.Vec2[float32]{X: 55, Y: 50}, true, false}, Expected: util.Vec2[float32]{X: 80, Y: 90}},
+	}
+
+	util.IterateTestCases(cases, t, func(testCase util.TestCase[TestInput, util.Vec2[float32]]) {
+		collisionSys := collision.NewCollisionSystem(quadtree.Rect{X: -500, Y: -500, W: 1000, H: 1000})
+		world := PhysicsWorld{Gravity: util.Vec2[float32]{X: 0, Y: 1000}}
+		collision.NewCollider(core.WALL_LAYER, "floor", quadtree.Rect{X: -500, Y: 100, W: 1000, H: 10}, &collisionSys, &collisionSys, nil, nil)
+		if testCase.Input.isWalled {
+			collision.NewCollider(core.WALL_LAYER, "wall", quadtree.Rect{X: 90, Y: 0, W: 10, H: 100}, &collisionSys, &collisionSys, nil, nil)
+		}
+
+		// the platform starts beside the body, or under the floor when rising
+		start := util.Vec2[float32]{X: 0, Y: 90}
+		if testCase.Input.to.Y != 90 {
+			start = util.Vec2[float32]{X: 55, Y: 110}
+		}
+		platform := newPlatformInScene([]util.Vec2[float32]{start, testCase.Input.to}, 1000, EaseLinear, &collisionSys)
+		platform.collider.SetOneWay(testCase.Input.isOneWay)
+		rb, body := newRigidBodyInScene(core.PLAYER_LAYER, "body", util.Vec2[float32]{X: 80, Y: 90}, util.Vec2[float32]{}, &collisionSys, &world, false)
+
+		for range 1000 / 20 {
+			platform.OnUpdate(20, nil)
+			rb.OnUpdate(20, nil)
+		}
+
+		require.InDelta(t, testCase.Expected.X, body.Pos.X, 0.1)
+		require.InDelta(t, testCase.Expected.Y, body.Pos.Y, 0.1)
+	})
+}
+
+func TestNewMovingPlatformShouldMoveLinearlyBetweenCopiedWaypointsWithoutAnEasing(t *testing.T) {
+	collisionSys := collision.NewCollisionSystem(quadtree.Rect{X: -500, Y: -500, W: 1000, H: 1000})
+	waypoints := []util.Vec2[float32]{{X: 0, Y: 0}, {X: 100, Y: 0}}
+	platform := newPlatformInScene(waypoints, 1000, nil, &collisionSys)
+
+	// changing the waypoints given does not change the path of the platform
+	waypoints[1] = util.Vec2[float32]{X: 0, Y: 100}
+	platform.OnUpdate(250, nil)
+
+	require.Equal(t, util.Vec2[float32]{X: 25, Y: 0}, platform.Pos)
+}
+
+func TestNewMovingPlatformShouldPanic(t *testing.T) {
+	collisionSys := collision.NewCollisionSystem(quadtree.Rect{X: -500, Y: -500, W: 1000, H: 1000})
+
+	require.PanicsWithValue(t, "a moving platform needs at least two waypoints", func() {
+		newPlatformInScene([]util.Vec2[float32]{{X: 0, Y: 0}}, 1000, EaseLinear, &collisionSys)
+	})
+	require.PanicsWithValue(t, "the travel time of a moving platform must be positive", func() {
+		newPlatformInScene([]util.Vec2[float32]{{X: 0, Y: 0}, {X: 10, Y: 0}}, 0, EaseLinear, &collisionSys)
+	})
+}
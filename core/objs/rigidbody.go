@@ -1,6 +1,8 @@
 package objs
 
 import (
+	"math"
+
 	"github.com/TheRaizer/GolangGame/core"
 	"github.com/TheRaizer/GolangGame/core/collision"
 	"github.com/TheRaizer/GolangGame/util"
@@ -8,16 +10,59 @@ import (
 	"github.com/veandco/go-sdl2/sdl"
 )
 
+// The settings shared by the rigid bodies of a game
+type PhysicsWorld struct {
+	Gravity util.Vec2[float32] // the acceleration the bodies fall with, in pixels per second squared, none for a top down game
+}
+
 type RigidBody struct {
 	core.BaseGameObject
 
-	Velocity     *util.Vec2[float32]
-	collider     *collision.Collider
-	collisionSys collision.CollisionSystemMediator
-	restriction  uint8 // 0 = horizontal, 1 = vertical, 2 = none
-	isContinous  bool
+	Velocity      *util.Vec2[float32] // in pixels per second
+	force         util.Vec2[float32]  // the sum of the forces added since the last update
+	mass          float32
+	gravityScale  float32 // how strongly the gravity of the world pulls on the body
+	drag          float32 // the fraction of its velocity the body loses every second, when it is small
+	maxFallSpeed  float32
+	restitution   float32 // how much of their speed towards each other bodies keep when bouncing off of the body
+	friction      float32
+	isPushing     bool // whether the body is moving another body out of its way, which does not push it back
+	collider      *collision.Collider
+	collisionSys  collision.CollisionSystemMediator
+	world         *PhysicsWorld
+	contacts      []collision.Manifold           // reused between updates so that detecting contacts does not allocate
+	ignored       map[*collision.Collider]uint64 // colliders passed through, with the milliseconds left to ignore them
+	groundBuf     []*collision.Collider          // reused by DropThrough
+	queryIgnore   []*collision.Collider          // reused for the colliders the ground snapping query ignores
+	isGrounded    bool                           // whether the body was left standing on ground it can walk on by the last update
+	isOnWallLeft  bool
+	isOnWallRight bool
+	isOnCeiling   bool
+	groundNormal  util.Vec2[float32] // from the body in to the ground it was left standing on by the last update
+	isContinous   bool
 }
 
+// how far below the top of a one-way platform the bottom of a body can have sunk and still land on it
+const oneWayTolerance = 0.01
+
+// how far from a collider a body can be while still touching it, such as when standing on the ground
+const contactTolerance = 0.05
+
+// how many times the contacts are resolved in an update, as resolving one contact can move the body back in to
+// a contact resolved before it, such as when falling in to the corner between two steep slopes
+const resolveIterations = 4
+
+// the steepest slope, in radians, that bodies can walk on rather than being stopped by
+const maxSlopeAngle = 50 * math.Pi / 180
+
+var (
+	minGroundNormalY = float32(math.Cos(maxSlopeAngle))
+	maxSlopeRise     = float32(math.Tan(maxSlopeAngle)) // how far up the steepest slope rises for every unit across
+)
+
+// Creates a rigid body that moves its parent with the collider, and attaches itself to the collider
+// so that the other rigid bodies colliding with it push it rather than being stopped by it.
+// The body falls with the gravity of the world, which it shares with the other bodies of the game.
 func NewRigidBody(
 	layer int,
 	name string,
@@ -25,87 +70,533 @@ func NewRigidBody(
 	gameObjectStore core.GameObjectStore,
 	collider *collision.Collider,
 	collisionSys collision.CollisionSystemMediator,
+	world *PhysicsWorld,
 	isContinous bool,
-) RigidBody {
-	return RigidBody{
+) *RigidBody {
+	if world == nil {
+		panic("a rigid body needs a physics world")
+	}
+
+	rb := RigidBody{
 		BaseGameObject: core.NewBaseGameObject(layer, name, util.Vec2[float32]{}, gameObjectStore),
 		Velocity:       &initialVel,
+		mass:           1,
+		gravityScale:   1,
+		maxFallSpeed:   float32(math.Inf(1)),
 		collider:       collider,
 		collisionSys:   collisionSys,
+		world:          world,
+		ignored:        make(map[*collision.Collider]uint64),
 		isContinous:    isContinous,
 	}
+	collider.SetBody(&rb)
+
+	return &rb
 }
 
+// Integrates the forces on the body in to its velocity and then its velocity in to its position, with semi-implicit Euler
 func (rb *RigidBody) OnUpdate(dt uint64, surface *sdl.Surface) {
+	seconds := float32(dt) / 1000
+	gravity := rb.world.Gravity
+
+	acceleration := gravity.Multiply(rb.gravityScale).Add(rb.force.Divide(rb.mass))
+	*rb.Velocity = rb.Velocity.Add(acceleration.Multiply(seconds))
+	rb.force = util.Vec2[float32]{}
+
+	// drag applied implicitly, so that a long update slows the body down rather than reversing it
+	*rb.Velocity = rb.Velocity.Divide(1 + rb.drag*seconds)
+	rb.Velocity.Y = min(rb.Velocity.Y, rb.maxFallSpeed)
+
 	if rb.Velocity.X != 0 || rb.Velocity.Y != 0 {
-		distX := float32(rb.Velocity.X) * (float32(dt) / 1000)
-		distY := float32(rb.Velocity.Y) * (float32(dt) / 1000)
+		rb.detectCollision(rb.Velocity.X*seconds, rb.Velocity.Y*seconds)
+	}
+
+	for collider, remaining := range rb.ignored {
+		if remaining <= dt {
+			delete(rb.ignored, collider)
+		} else {
+			rb.ignored[collider] = remaining - dt
+		}
+	}
+}
+
+// Pushes the body with the force until the next update, in pixels per second squared for every unit of mass.
+// Forces such as thrust or wind are added every update that they push the body for.
+func (rb *RigidBody) AddForce(force util.Vec2[float32]) {
+	rb.force = rb.force.Add(force)
+}
+
+// Changes the velocity of the body at once by the impulse divided by its mass, such as for a jump, knockback or explosion
+func (rb *RigidBody) AddImpulse(impulse util.Vec2[float32]) {
+	*rb.Velocity = rb.Velocity.Add(impulse.Divide(rb.mass))
+}
+
+func (rb *RigidBody) Mass() float32 {
+	return rb.mass
+}
+
+// Sets how much the body resists forces and impulses, which is 1 by default
+func (rb *RigidBody) SetMass(mass float32) {
+	if mass <= 0 {
+		panic("the mass of a rigid body must be positive")
+	}
+
+	rb.mass = mass
+}
+
+func (rb *RigidBody) GravityScale() float32 {
+	return rb.gravityScale
+}
+
+// Scales the gravity of the world for the body, which is 1 by default.
+// A scale of 0 makes the body float, such as for projectiles that fly straight.
+func (rb *RigidBody) SetGravityScale(gravityScale float32) {
+	rb.gravityScale = gravityScale
+}
+
+func (rb *RigidBody) Drag() float32 {
+	return rb.drag
+}
+
+// Sets the linear drag that slows the body down in proportion to its velocity, which is none by default
+func (rb *RigidBody) SetDrag(drag float32) {
+	if drag < 0 {
+		panic("the drag of a rigid body cannot be negative")
+	}
+
+	rb.drag = drag
+}
+
+func (rb *RigidBody) Restitution() float32 {
+	return rb.restitution
+}
+
+// Sets how bouncy the body is when colliding with other rigid bodies, from 0 where they stop moving towards each other
+// to 1 where they bounce apart as fast as they collided. The bouncier of the two bodies is used.
+func (rb *RigidBody) SetRestitution(restitution float32) {
+	if restitution < 0 || restitution > 1 {
+		panic("the restitution of a rigid body must be between 0 and 1")
+	}
+
+	rb.restitution = restitution
+}
 
-		rb.detectCollision(distX, distY)
+func (rb *RigidBody) Friction() float32 {
+	return rb.friction
+}
+
+// Sets how much the body resists sliding along other rigid bodies it collides with, which is none by default.
+// The geometric mean of the friction of the two bodies is used, so either body can make the contact frictionless.
+func (rb *RigidBody) SetFriction(friction float32) {
+	if friction < 0 {
+		panic("the friction of a rigid body cannot be negative")
+	}
+
+	rb.friction = friction
+}
+
+func (rb *RigidBody) MaxFallSpeed() float32 {
+	return rb.maxFallSpeed
+}
+
+// Caps the downwards velocity of the body, in pixels per second, which is not capped by default
+func (rb *RigidBody) SetMaxFallSpeed(maxFallSpeed float32) {
+	rb.maxFallSpeed = maxFallSpeed
+}
+
+// Lets the body pass through the collider for the given number of milliseconds
+func (rb *RigidBody) IgnoreCollider(collider *collision.Collider, duration uint64) {
+	rb.ignored[collider] = duration
+}
+
+// Ignores the one-way platforms the body is standing on for the given number of milliseconds,
+// which is long enough to drop through them when it is longer than it takes to fall below their top edge
+func (rb *RigidBody) DropThrough(duration uint64) {
+	rect := rb.collider.Rect
+	feet := quadtree.Rect{X: rect.X, Y: rect.Bottom(), W: rect.W, H: 1}
+	filter := collision.QueryFilter{Mask: rb.collisionSys.Layers().Mask(rb.Layer(), core.COLLIDE)}
+
+	rb.groundBuf = rb.collisionSys.OverlapBox(feet, filter, rb.groundBuf[:0])
+	for _, platform := range rb.groundBuf {
+		if platform.IsOneWay() {
+			rb.IgnoreCollider(platform, duration)
+		}
+	}
+}
+
+// Moves the parent by the distance, restricted by the colliders it would collide with on the way, and returns how far it moved.
+// Only the colliders around the new position are tested, unless the body is continuous, in which case every collider
+// along the way is tested, so that a body moving further than the size of a collider in one update cannot pass through it.
+func (rb *RigidBody) detectCollision(distX float32, distY float32) (float32, float32) {
+	// bodies walking on a slope move along it rather than in to it or off of it, so they keep their speed
+	onGround := rb.isGrounded && rb.Velocity.Y >= 0
+	if onGround {
+		distY -= distX * rb.groundNormal.X / rb.groundNormal.Y
+	}
+
+	rb.detectContacts(distX, distY)
+	if rb.isContinous {
+		distX, distY = rb.sweepMovement(distX, distY, onGround)
+	}
+
+	distX, distY = rb.restrictMovement(distX, distY, onGround)
+	rb.updateContactState(distX, distY)
+
+	// keeps the body on the ground when walking down a slope or over the top of one, rather than leaving it and bouncing
+	if onGround {
+		if snap, ok := rb.snapToGround(distX, distY); ok {
+			distY += snap
+			rb.isGrounded = true
+		}
 	}
+
+	rb.Parent().UpdatePos(distX, distY)
+	return distX, distY
+}
+
+// Moves the parent by the distance, restricted by the colliders it would collide with on the way, without walking along
+// the ground or finding which sides of the body are left touching colliders, which are left to the updates of the body.
+// Used for moving the body when it is pulled by a joint, which can happen many times in one step.
+func (rb *RigidBody) move(distX float32, distY float32) {
+	rb.detectContacts(distX, distY)
+	if rb.isContinous {
+		distX, distY = rb.sweepMovement(distX, distY, false)
+	}
+
+	distX, distY = rb.restrictMovement(distX, distY, false)
+	rb.Parent().UpdatePos(distX, distY)
 }
 
-// TODO: tackle the tunelling problem
-func (rb *RigidBody) detectCollision(distX float32, distY float32) {
-	// TODO: to tackle the tunelling problem instead of checking collisions with future position we can
-	// detect collisions with the parallelogram from the current position to the future position.
-	// if there are any collisions then deal with them accordingly
+// Detects the contacts around where the body moves to, and every contact along the way when the body is continuous
+func (rb *RigidBody) detectContacts(distX float32, distY float32) {
+	// grown to include the colliders being touched, such as the ground being stood on
 	newRect := quadtree.Rect{
-		X: int32(rb.collider.Pos.X + distX),
-		Y: int32(rb.collider.Pos.Y + distY),
-		W: rb.collider.Rect.W,
-		H: rb.collider.Rect.H,
+		X: rb.collider.Rect.X + distX - contactTolerance,
+		Y: rb.collider.Rect.Y + distY - contactTolerance,
+		W: rb.collider.Rect.W + contactTolerance*2,
+		H: rb.collider.Rect.H + contactTolerance*2,
+	}
+	if rb.isContinous {
+		newRect = newRect.Union(rb.collider.Rect)
 	}
+
 	// precompute possible collision for dynamic movement
-	els := rb.collisionSys.DetectCollisions(newRect)
-	rb.restrictParent(els)
-	rb.moveOnRestriction(distX, distY)
-}
-
-func (rb *RigidBody) moveOnRestriction(distX float32, distY float32) {
-	if rb.restriction == 0 {
-		rb.Velocity.X = 0
-		rb.Parent().UpdatePos(0, distY)
-	}
-
-	if rb.restriction == 1 {
-		rb.Velocity.Y = 0
-		rb.Parent().UpdatePos(distX, 0)
-	}
-
-	if rb.restriction == 2 {
-		rb.Parent().UpdatePos(distX, distY)
-	}
-}
-
-// restricts the parent object according to colliding elements and updates rb.restriction with corresponding restriction
-func (rb *RigidBody) restrictParent(els []quadtree.QuadElement) {
-	for _, el := range els {
-		obj := rb.GameObjectStore.GetGameObject(el.Id)
-		if obj.Layer() != rb.Layer() {
-			overlapLeft := rb.collider.Rect.Right() - el.Rect.X
-			overlapRight := el.Rect.Right() - rb.collider.Rect.X
-			overlapTop := rb.collider.Rect.Bottom() - el.Rect.Y
-			overlapBottom := el.Rect.Bottom() - rb.collider.Rect.Y
-
-			if min(overlapLeft, overlapRight) < min(overlapTop, overlapBottom) {
-				if overlapLeft < overlapRight {
-					rb.Parent().UpdatePos(-float32(overlapLeft), 0)
-				} else {
-					rb.Parent().UpdatePos(float32(overlapRight), 0)
-				}
-				rb.restriction = 0
-				return
-			} else {
-				if overlapTop < overlapBottom {
-					rb.Parent().UpdatePos(0, -float32(overlapTop))
-				} else {
-					rb.Parent().UpdatePos(0, float32(overlapBottom))
-				}
-				rb.restriction = 1
-				return
+	rb.contacts = rb.collisionSys.DetectContacts(rb.collider, newRect, rb.contacts[:0])
+}
+
+// Stops the movement at the first collider the body would hit on the way, by the time of impact of the swept collider,
+// and slides the rest of the movement along the collider. The ground walked along is left to restrictMovement.
+func (rb *RigidBody) sweepMovement(distX float32, distY float32, onGround bool) (float32, float32) {
+	dist := util.Vec2[float32]{X: distX, Y: distY}
+	first, normal, isHit := float32(1), util.Vec2[float32]{}, false
+
+	for _, contact := range rb.contacts {
+		if !rb.isBlockedBy(contact.Other) || (onGround && rb.isUnderFeet(contact)) {
+			continue
+		}
+
+		impact, impactNormal, ok := rb.collider.TimeOfImpact(dist, contact.Other)
+		if !ok || impact >= first || dist.Dot(impactNormal) <= 0 {
+			continue
+		}
+
+		// bodies only hit the top edge of one-way platforms, from above
+		if contact.Other.IsOneWay() &&
+			(impactNormal.Y <= 0 || contact.Other.Rect.Y-rb.collider.Rect.Bottom() < -oneWayTolerance) {
+			continue
+		}
+
+		first, normal, isHit = impact, impactNormal, true
+	}
+
+	if !isHit {
+		return distX, distY
+	}
+
+	remaining := dist.Multiply(1 - first)
+	remaining = remaining.Sub(normal.Multiply(remaining.Dot(normal)))
+
+	if speed := rb.Velocity.Dot(normal); speed > 0 {
+		*rb.Velocity = rb.Velocity.Sub(normal.Multiply(speed))
+	}
+
+	moved := dist.Multiply(first).Add(remaining)
+	return moved.X, moved.Y
+}
+
+// Removes the part of the movement that would take the parent past each collider it is in contact with,
+// which also pushes the parent out of the colliders it already overlaps, and stops the velocity towards them.
+// Ground that can be walked on only stops the body vertically, so that it walks up slopes at the same speed.
+func (rb *RigidBody) restrictMovement(distX float32, distY float32, onGround bool) (float32, float32) {
+	for i := 0; i < resolveIterations; i++ {
+		var isRestricted bool
+		if distX, distY, isRestricted = rb.resolveContacts(distX, distY, onGround); !isRestricted {
+			break
+		}
+	}
+
+	return distX, distY
+}
+
+// Resolves each contact in turn, and returns whether any of them restricted the movement
+func (rb *RigidBody) resolveContacts(distX float32, distY float32, onGround bool) (float32, float32, bool) {
+	isRestricted := false
+
+	for i := range rb.contacts {
+		contact := &rb.contacts[i]
+		if !rb.isBlockedBy(contact.Other) {
+			continue
+		}
+
+		if contact.Other.IsOneWay() {
+			landedY := rb.landOn(contact.Other, distY)
+			isRestricted = isRestricted || landedY != distY
+			distY = landedY
+			continue
+		}
+
+		normal := contact.Normal
+		gap := -contact.Depth
+		dist := util.Vec2[float32]{X: distX, Y: distY}
+		towards := dist.Dot(normal)
+
+		// the foot of a slope, or the seam between two colliders of the ground, is in front of the body
+		// before it moves but is walked on to after it moves, so it is resolved from where the body moves to
+		if onGround && !isWalkable(normal) && rb.isUnderFeet(*contact) {
+			moved := rb.collider.ContactAfter(dist, contact.Other)
+			normal, gap, towards = moved.Normal, -moved.Depth, 0
+		}
+
+		// moving platforms push the bodies they run in to out of their way themselves, so a body crushed between
+		// a platform and a wall is left overlapping the platform rather than pushing itself in to the wall
+		if _, ok := contact.Other.Body().(*MovingPlatform); ok {
+			gap = max(gap, 0)
+		}
+
+		if towards <= gap {
+			continue
+		}
+
+		isRestricted = true
+
+		if other, ok := contact.Other.Body().(*RigidBody); ok && !other.isPushing {
+			var moved float32
+			distX, distY, moved = rb.push(other, normal, towards-gap, distX, distY)
+			// the other body moved away, leaving a larger gap to it
+			contact.Depth -= moved
+			continue
+		}
+
+		if isWalkable(normal) {
+			distY -= (towards - gap) / normal.Y
+			rb.Velocity.Y = min(rb.Velocity.Y, 0)
+			continue
+		}
+
+		dist = dist.Sub(normal.Multiply(towards - gap))
+		distX, distY = dist.X, dist.Y
+
+		if speed := rb.Velocity.Dot(normal); speed > 0 {
+			*rb.Velocity = rb.Velocity.Sub(normal.Multiply(speed))
+		}
+	}
+
+	return distX, distY, isRestricted
+}
+
+// Shares the overlap the movement would leave with the other rigid body between the two bodies by their masses,
+// moving the other body out of the way as far as it can move, and exchanges their velocities by their masses.
+// Returns the restricted movement, and how far the other body moved away along the normal.
+func (rb *RigidBody) push(
+	other *RigidBody,
+	normal util.Vec2[float32],
+	overlap float32,
+	distX float32,
+	distY float32,
+) (float32, float32, float32) {
+	invMass, otherInvMass := 1/rb.mass, 1/other.mass
+	rb.collide(other, normal, invMass, otherInvMass)
+
+	share := overlap * otherInvMass / (invMass + otherInvMass)
+	rb.isPushing = true
+	movedX, movedY := other.detectCollision(normal.X*share, normal.Y*share)
+	rb.isPushing = false
+
+	// the body cannot move in to the other body any further or faster than the other body moves out of the way,
+	// such as when pushing a crate against a wall
+	moved := util.Vec2[float32]{X: movedX, Y: movedY}.Dot(normal)
+	dist := util.Vec2[float32]{X: distX, Y: distY}.Sub(normal.Multiply(overlap - moved))
+
+	if speed := rb.Velocity.Sub(*other.Velocity).Dot(normal); speed > 0 {
+		*rb.Velocity = rb.Velocity.Sub(normal.Multiply(speed))
+	}
+
+	return dist.X, dist.Y, moved
+}
+
+// Applies the impulse that stops the two bodies moving towards each other along the normal, scaled up by their restitution
+// to bounce them apart, and the friction impulse that slows them sliding along each other, which at most stops the sliding
+func (rb *RigidBody) collide(other *RigidBody, normal util.Vec2[float32], invMass float32, otherInvMass float32) {
+	relative := rb.Velocity.Sub(*other.Velocity)
+	speed := relative.Dot(normal)
+	if speed <= 0 {
+		return
+	}
+
+	impulse := (1 + max(rb.restitution, other.restitution)) * speed / (invMass + otherInvMass)
+	rb.exchange(other, normal.Multiply(impulse), invMass, otherInvMass)
+
+	sliding := relative.Sub(normal.Multiply(speed))
+	slidingSpeed := sliding.Length()
+	if slidingSpeed == 0 {
+		return
+	}
+
+	friction := float32(math.Sqrt(float64(rb.friction * other.friction)))
+	frictionImpulse := min(slidingSpeed/(invMass+otherInvMass), friction*impulse)
+	rb.exchange(other, sliding.Divide(slidingSpeed).Multiply(frictionImpulse), invMass, otherInvMass)
+}
+
+// Transfers the impulse from the body to the other body
+func (rb *RigidBody) exchange(other *RigidBody, impulse util.Vec2[float32], invMass float32, otherInvMass float32) {
+	*rb.Velocity = rb.Velocity.Sub(impulse.Multiply(invMass))
+	*other.Velocity = other.Velocity.Add(impulse.Multiply(otherInvMass))
+}
+
+// Finds which sides of the body are left touching the colliders that block it after moving, and the normal of the ground
+func (rb *RigidBody) updateContactState(distX float32, distY float32) {
+	rb.isGrounded, rb.isOnWallLeft, rb.isOnWallRight, rb.isOnCeiling = false, false, false, false
+	dist := util.Vec2[float32]{X: distX, Y: distY}
+
+	for _, contact := range rb.contacts {
+		if !rb.isBlockedBy(contact.Other) {
+			continue
+		}
+
+		normal, gap := contact.Normal, -contact.Depth
+		if contact.Other.IsOneWay() {
+			normal, gap = util.Vec2[float32]{X: 0, Y: 1}, contact.Other.Rect.Y-rb.collider.Rect.Bottom()
+			if gap < -oneWayTolerance {
+				continue
+			}
+		}
+
+		if gap-dist.Dot(normal) > contactTolerance {
+			continue
+		}
+
+		switch {
+		case isWalkable(normal):
+			if !rb.isGrounded || normal.Y > rb.groundNormal.Y {
+				rb.groundNormal = normal
 			}
+			rb.isGrounded = true
+		case normal.Y <= -minGroundNormalY:
+			rb.isOnCeiling = true
+		case normal.X < 0:
+			rb.isOnWallLeft = true
+		case normal.X > 0:
+			rb.isOnWallRight = true
 		}
 	}
-	rb.restriction = 2
+}
+
+// Whether the body was left standing on ground it can walk on by the last update, such as for deciding if it can jump
+func (rb *RigidBody) IsGrounded() bool {
+	return rb.isGrounded
+}
+
+// Returns the normal from the body in to the ground it is standing on, which is straight down on flat ground
+func (rb *RigidBody) GroundNormal() util.Vec2[float32] {
+	return rb.groundNormal
+}
+
+// Whether the body was left touching a wall on its left by the last update, such as for wall jumping
+func (rb *RigidBody) IsOnWallLeft() bool {
+	return rb.isOnWallLeft
+}
+
+// Whether the body was left touching a wall on its right by the last update
+func (rb *RigidBody) IsOnWallRight() bool {
+	return rb.isOnWallRight
+}
+
+// Whether the body was left touching a ceiling by the last update, such as for cutting a jump short
+func (rb *RigidBody) IsOnCeiling() bool {
+	return rb.isOnCeiling
+}
+
+// Returns how far down the ground is below the body once it has moved, when it is close enough for the body
+// to have been walking along it, which is at most as far as the steepest slope drops over the distance moved across
+func (rb *RigidBody) snapToGround(distX float32, distY float32) (float32, bool) {
+	if rb.collider.IsTrigger() {
+		return 0, false
+	}
+
+	rb.queryIgnore = append(rb.queryIgnore[:0], rb.collider)
+	for collider := range rb.ignored {
+		rb.queryIgnore = append(rb.queryIgnore, collider)
+	}
+
+	filter := collision.QueryFilter{
+		Mask:           rb.collisionSys.Layers().Mask(rb.Layer(), core.COLLIDE),
+		Ignore:         rb.queryIgnore,
+		IgnoreTriggers: true,
+	}
+
+	rect := rb.collider.Rect
+	rect.X, rect.Y = rect.X+distX, rect.Y+distY
+	maxSnap := float32(math.Abs(float64(distX)))*maxSlopeRise + contactTolerance
+
+	hit, ok := rb.collisionSys.BoxCast(rect, util.Vec2[float32]{X: 0, Y: 1}, maxSnap, filter)
+	normal := hit.Normal.Multiply(-1)
+	if !ok || !isWalkable(normal) {
+		return 0, false
+	}
+
+	rb.groundNormal = normal
+	return hit.Distance, true
+}
+
+// whether the closest point of the contact is level with the bottom of the body,
+// where the highest of the points is used when they are equally close, such as along the face of a wall
+func (rb *RigidBody) isUnderFeet(contact collision.Manifold) bool {
+	closest := contact.Points[0]
+	if contact.PointCount == 2 {
+		separation := contact.Points[0].Dot(contact.Normal)
+		otherSeparation := contact.Points[1].Dot(contact.Normal)
+
+		if otherSeparation < separation-contactTolerance ||
+			(otherSeparation <= separation+contactTolerance && contact.Points[1].Y < closest.Y) {
+			closest = contact.Points[1]
+		}
+	}
+
+	return closest.Y >= rb.collider.Rect.Bottom()-contactTolerance
+}
+
+// whether the normal from a body in to a collider is of ground that is flat enough to walk on
+func isWalkable(normal util.Vec2[float32]) bool {
+	return normal.Y >= minGroundNormalY
+}
+
+// One-way platforms only stop the body vertically, when it is falling and was above the top edge of the platform
+// before this step, so that bodies jumping up through a platform or walking in to its sides pass through it
+func (rb *RigidBody) landOn(platform *collision.Collider, distY float32) float32 {
+	gap := platform.Rect.Y - rb.collider.Rect.Bottom()
+	if distY <= 0 || gap < -oneWayTolerance || distY <= gap {
+		return distY
+	}
+
+	rb.Velocity.Y = min(rb.Velocity.Y, 0)
+	return gap
+}
+
+// Only colliding layers block movement, triggers, ignored layers and ignored colliders are passed through
+func (rb *RigidBody) isBlockedBy(other *collision.Collider) bool {
+	if _, ok := rb.ignored[other]; ok || rb.collider.IsTrigger() || other.IsTrigger() {
+		return false
+	}
+
+	return rb.collisionSys.Layers().Interaction(rb.Layer(), other.Layer()) == core.COLLIDE
 }
@@ -2,6 +2,7 @@ package objs
 
 import (
 	"fmt"
+	"math"
 	"testing"
 
 	"github.com/TheRaizer/GolangGame/core"
@@ -15,6 +16,8 @@ import (
 type MockCollisionSystem struct {
 	mock.Mock
 	elementsToDetect []quadtree.QuadElement
+	colliders        map[string]*collision.Collider // used for the detected elements with the same id
+	registry         *core.LayerRegistry            // the default layers are used when nil
 }
 
 func (collisionSys *MockCollisionSystem) DetectCollisions(rect quadtree.Rect) []quadtree.QuadElement {
@@ -22,9 +25,85 @@ func (collisionSys *MockCollisionSystem) DetectCollisions(rect quadtree.Rect) []
 	return collisionSys.elementsToDetect
 }
 
+// any element without a collider will restrict movement as a layer 0 collider covering its rect
+// (so any rb that does not have layer 0 will be blocked by this element)
+func (collisionSys *MockCollisionSystem) DetectContacts(
+	collider *collision.Collider,
+	rect quadtree.Rect,
+	buf []collision.Manifold,
+) []collision.Manifold {
+	collisionSys.Called(rect)
+	for _, el := range collisionSys.elementsToDetect {
+		other, ok := collisionSys.colliders[el.Id]
+		if !ok {
+			other = collision.NewCollider(0, el.Id, el.Rect, collisionSys, collisionSys, nil, nil)
+		}
+
+		buf = append(buf, collider.ContactWith(other))
+	}
+
+	return buf
+}
+
 func (collisionSys *MockCollisionSystem) UpdateCollider(id string, oldRect quadtree.Rect, newRect quadtree.Rect) {
 }
 
+var layers = core.NewLayerRegistry()
+
+func (collisionSys *MockCollisionSystem) Layers() *core.LayerRegistry {
+	if collisionSys.registry != nil {
+		return collisionSys.registry
+	}
+
+	return &layers
+}
+
+func (collisionSys *MockCollisionSystem) EnableCollider(collider *collision.Collider) {}
+
+func (collisionSys *MockCollisionSystem) DisableCollider(collider *collision.Collider) {}
+
+// only the given colliders can be overlapped
+func (collisionSys *MockCollisionSystem) OverlapBox(
+	rect quadtree.Rect,
+	filter collision.QueryFilter,
+	buf []*collision.Collider,
+) []*collision.Collider {
+	for _, collider := range collisionSys.colliders {
+		if collider.Rect.Intersects(rect) {
+			buf = append(buf, collider)
+		}
+	}
+
+	return buf
+}
+
+func (collisionSys *MockCollisionSystem) OverlapCircle(
+	center util.Vec2[float32],
+	radius float32,
+	filter collision.QueryFilter,
+	buf []*collision.Collider,
+) []*collision.Collider {
+	return buf
+}
+
+func (collisionSys *MockCollisionSystem) Raycast(
+	origin util.Vec2[float32],
+	direction util.Vec2[float32],
+	maxDistance float32,
+	filter collision.QueryFilter,
+) (collision.Hit, bool) {
+	return collision.Hit{}, false
+}
+
+func (collisionSys *MockCollisionSystem) BoxCast(
+	rect quadtree.Rect,
+	direction util.Vec2[float32],
+	maxDistance float32,
+	filter collision.QueryFilter,
+) (collision.Hit, bool) {
+	return collision.Hit{}, false
+}
+
 func (collisionSys *MockCollisionSystem) RegisterObject(obj *collision.Collider) {}
 
 func (collisionSys *MockCollisionSystem) DeregisterObject(obj *collision.Collider) {}
@@ -43,8 +122,6 @@ func (store *MockGameObjectStore) RemoveGameObject(id string) {
 	store.Called(id)
 }
 
-// any object that will restrict movement will have layer 0
-// (so any rb that does not have layer 0 will be blocked by this object)
 func (store *MockGameObjectStore) GetGameObject(id string) core.GameObject {
 	store.Called(id)
 	mockObj := core.NewBaseGameObject(0, id, util.Vec2[float32]{}, store)
@@ -60,7 +137,7 @@ func TestOnUpdateShouldMoveParentWhenNotRestricted(t *testing.T) {
 
 	type TestExpected struct { // the new position of the parent element
 		pos util.Vec2[float32]
-		// the rectangle used to detect collisions in the future position
+		// the rectangle used to detect collisions in the future position, grown to include the colliders touching it
 		rect quadtree.Rect
 	}
 
@@ -80,7 +157,7 @@ func TestOnUpdateShouldMoveParentWhenNotRestricted(t *testing.T) {
 			Expected: TestExpected{
 				pos: util.Vec2[float32]{X: float32(10), Y: float32(10)},
 				rect: quadtree.Rect{
-					X: 10, Y: 10, W: 5, H: 5,
+					X: 10 - contactTolerance, Y: 10 - contactTolerance, W: 5 + contactTolerance*2, H: 5 + contactTolerance*2,
 				},
 			},
 		},
@@ -94,7 +171,7 @@ func TestOnUpdateShouldMoveParentWhenNotRestricted(t *testing.T) {
 			Expected: TestExpected{
 				pos: util.Vec2[float32]{X: 5 + float32(2)*2, Y: 5},
 				rect: quadtree.Rect{
-					X: 9, Y: 5, W: 5, H: 5,
+					X: 9 - contactTolerance, Y: 5 - contactTolerance, W: 5 + contactTolerance*2, H: 5 + contactTolerance*2,
 				},
 			},
 		},
@@ -108,7 +185,7 @@ func TestOnUpdateShouldMoveParentWhenNotRestricted(t *testing.T) {
 			Expected: TestExpected{
 				pos: util.Vec2[float32]{X: 3, Y: 5},
 				rect: quadtree.Rect{
-					X: 3, Y: 5, W: 15, H: 15,
+					X: 3 - contactTolerance, Y: 5 - contactTolerance, W: 15 + contactTolerance*2, H: 15 + contactTolerance*2,
 				},
 			},
 		},
@@ -122,7 +199,7 @@ func TestOnUpdateShouldMoveParentWhenNotRestricted(t *testing.T) {
 			Expected: TestExpected{
 				pos: util.Vec2[float32]{X: 3, Y: 5},
 				rect: quadtree.Rect{
-					X: 3, Y: 5, W: 15, H: 15,
+					X: 3 - contactTolerance, Y: 5 - contactTolerance, W: 15 + contactTolerance*2, H: 15 + contactTolerance*2,
 				},
 			},
 		},
@@ -136,7 +213,7 @@ func TestOnUpdateShouldMoveParentWhenNotRestricted(t *testing.T) {
 			Expected: TestExpected{
 				pos: util.Vec2[float32]{X: 50 - float32(16)*2, Y: 5},
 				rect: quadtree.Rect{
-					X: 18, Y: 5, W: 15, H: 15,
+					X: 18 - contactTolerance, Y: 5 - contactTolerance, W: 15 + contactTolerance*2, H: 15 + contactTolerance*2,
 				},
 			},
 		},
@@ -163,13 +240,13 @@ func TestOnUpdateShouldMoveParentWhenNotRestricted(t *testing.T) {
 			[]func(els []quadtree.QuadElement){},
 			nil,
 		)
-		rb := NewRigidBody(0, "rigidbody", testCase.Input.velocity, nil, collider, &collisionSys, false)
+		rb := NewRigidBody(0, "rigidbody", testCase.Input.velocity, nil, collider, &collisionSys, &PhysicsWorld{}, false)
 
 		rb.SetParent(&parent)
 		collider.SetParent(&parent)
 		rb.Pos = parent.Pos
 
-		collisionSys.Mock.On("DetectCollisions", testCase.Expected.rect)
+		collisionSys.Mock.On("DetectContacts", testCase.Expected.rect)
 
 		rb.OnUpdate(testCase.Input.dt, nil)
 
@@ -239,6 +316,22 @@ func TestOnUpdateShouldRestrictMovementDiscrete(t *testing.T) {
 				pos: util.Vec2[float32]{X: 18, Y: 2},
 			},
 		},
+		// every contact restricts movement, not only the first
+		{
+			Name: getName,
+			Input: TestInput{
+				dt:            10_000,
+				velocity:      util.Vec2[float32]{X: 1, Y: 1},
+				collisionRect: quadtree.Rect{X: 0, Y: 0, W: 5, H: 5},
+				elementsToDetect: []quadtree.QuadElement{
+					{Id: "wall", Rect: quadtree.Rect{X: 5, Y: 0, W: 5, H: 20}},
+					{Id: "floor", Rect: quadtree.Rect{X: 0, Y: 5, W: 20, H: 5}},
+				},
+			},
+			Expected: TestExpected{
+				pos: util.Vec2[float32]{X: 0, Y: 0},
+			},
+		},
 	}
 
 	util.IterateTestCases(cases, t, func(testCase util.TestCase[TestInput, TestExpected]) {
@@ -262,14 +355,13 @@ func TestOnUpdateShouldRestrictMovementDiscrete(t *testing.T) {
 			[]func(els []quadtree.QuadElement){},
 			&store,
 		)
-		rb := NewRigidBody(1, "rigidbody", testCase.Input.velocity, &store, collider, &collisionSys, false)
+		rb := NewRigidBody(1, "rigidbody", testCase.Input.velocity, &store, collider, &collisionSys, &PhysicsWorld{}, false)
 
 		rb.SetParent(&parent)
 		collider.SetParent(&parent)
 		rb.Pos = parent.Pos
 
-		store.On("GetGameObject", mock.Anything)
-		collisionSys.Mock.On("DetectCollisions", mock.Anything)
+		collisionSys.Mock.On("DetectContacts", mock.Anything)
 		rb.OnUpdate(testCase.Input.dt, nil)
 
 		require.Equal(t, testCase.Expected.pos, parent.Pos)
@@ -362,14 +454,13 @@ func TestOnUpdateShouldNotRestrictWhenSameLayer(t *testing.T) {
 			[]func(els []quadtree.QuadElement){},
 			&store,
 		)
-		rb := NewRigidBody(0, "rigidbody", testCase.Input.velocity, &store, collider, &collisionSys, false)
+		rb := NewRigidBody(0, "rigidbody", testCase.Input.velocity, &store, collider, &collisionSys, &PhysicsWorld{}, false)
 
 		rb.SetParent(&parent)
 		collider.SetParent(&parent)
 		rb.Pos = parent.Pos
 
-		store.On("GetGameObject", mock.Anything)
-		collisionSys.Mock.On("DetectCollisions", mock.Anything)
+		collisionSys.Mock.On("DetectContacts", mock.Anything)
 		rb.OnUpdate(testCase.Input.dt, nil)
 
 		require.Equal(t, testCase.Expected.pos, parent.Pos)
@@ -377,6 +468,729 @@ func TestOnUpdateShouldNotRestrictWhenSameLayer(t *testing.T) {
 	})
 }
 
-// TODO: implement this that should still restrict movement when dt and speed are large
-// that the future position is passed the restricting object
-// func TestOnUpdateShouldRestrictMovementContinuous(t *testing.T) {}
+func TestOnUpdateShouldNotRestrictWhenLayersDoNotCollide(t *testing.T) {
+	type TestInput struct {
+		layer       int
+		interaction core.Interaction
+	}
+
+	const NAME string = "should pass through walls when layer %d has the %v interaction with them"
+	getName := func(input TestInput) string {
+		return fmt.Sprintf(NAME, input.layer, input.interaction)
+	}
+
+	cases := []util.TestCase[TestInput, util.Vec2[float32]]{
+		{Name: getName, Input: TestInput{core.PLAYER_LAYER, core.TRIGGER}, Expected: util.Vec2[float32]{X: 10, Y: 0}},
+		{Name: getName, Input: TestInput{core.PLAYER_LAYER, core.IGNORE}, Expected: util.Vec2[float32]{X: 10, Y: 0}},
+		{Name: getName, Input: TestInput{core.ENEMY_LAYER, core.IGNORE}, Expected: util.Vec2[float32]{X: 10, Y: 0}},
+		// colliding pushes the parent back out of the wall and stops it horizontally
+		{Name: getName, Input: TestInput{core.PLAYER_LAYER, core.COLLIDE}, Expected: util.Vec2[float32]{X: -2, Y: 0}},
+	}
+
+	util.IterateTestCases(cases, t, func(testCase util.TestCase[TestInput, util.Vec2[float32]]) {
+		registry := core.NewLayerRegistry()
+		registry.SetInteraction(testCase.Input.layer, core.WALL_LAYER, testCase.Input.interaction)
+
+		store := MockGameObjectStore{}
+		parent := core.NewBaseGameObject(testCase.Input.layer, "parent", util.Vec2[float32]{}, &store)
+		collisionSys := MockCollisionSystem{
+			elementsToDetect: []quadtree.QuadElement{{Id: "wall", Rect: quadtree.Rect{X: 3, Y: 0, W: 5, H: 5}}},
+			registry:         &registry,
+		}
+		collider := collision.NewCollider(
+			testCase.Input.layer,
+			"rb_collider",
+			quadtree.Rect{X: 0, Y: 0, W: 5, H: 5},
+			&collisionSys,
+			&collisionSys,
+			[]func(els []quadtree.QuadElement){},
+			&store,
+		)
+		rb := NewRigidBody(testCase.Input.layer, "rigidbody", util.Vec2[float32]{X: 1, Y: 0}, &store, collider, &collisionSys, &PhysicsWorld{}, false)
+
+		rb.SetParent(&parent)
+		collider.SetParent(&parent)
+
+		collisionSys.Mock.On("DetectContacts", mock.Anything)
+		rb.OnUpdate(10_000, nil)
+
+		require.Equal(t, testCase.Expected, parent.Pos)
+	})
+}
+
+func TestOnUpdateShouldNotRestrictWhenTrigger(t *testing.T) {
+	type TestInput struct {
+		wallIsTrigger bool
+		rbIsTrigger   bool
+	}
+
+	const NAME string = "should only be blocked when neither collider is a trigger %+v"
+	getName := func(input TestInput) string {
+		return fmt.Sprintf(NAME, input)
+	}
+
+	cases := []util.TestCase[TestInput, util.Vec2[float32]]{
+		{Name: getName, Input: TestInput{false, false}, Expected: util.Vec2[float32]{X: -2, Y: 0}},
+		{Name: getName, Input: TestInput{true, false}, Expected: util.Vec2[float32]{X: 10, Y: 0}},
+		{Name: getName, Input: TestInput{false, true}, Expected: util.Vec2[float32]{X: 10, Y: 0}},
+	}
+
+	util.IterateTestCases(cases, t, func(testCase util.TestCase[TestInput, util.Vec2[float32]]) {
+		store := MockGameObjectStore{}
+		parent := core.NewBaseGameObject(core.PLAYER_LAYER, "parent", util.Vec2[float32]{}, &store)
+		collisionSys := MockCollisionSystem{
+			elementsToDetect: []quadtree.QuadElement{{Id: "wall", Rect: quadtree.Rect{X: 3, Y: 0, W: 5, H: 5}}},
+			colliders:        make(map[string]*collision.Collider),
+		}
+
+		wall := collision.NewCollider(core.WALL_LAYER, "wall", quadtree.Rect{X: 3, Y: 0, W: 5, H: 5}, &collisionSys, &collisionSys, nil, &store)
+		wall.SetTrigger(testCase.Input.wallIsTrigger)
+		collisionSys.colliders["wall"] = wall
+
+		collider := collision.NewCollider(core.PLAYER_LAYER, "rb_collider", quadtree.Rect{X: 0, Y: 0, W: 5, H: 5}, &collisionSys, &collisionSys, nil, &store)
+		collider.SetTrigger(testCase.Input.rbIsTrigger)
+		rb := NewRigidBody(core.PLAYER_LAYER, "rigidbody", util.Vec2[float32]{X: 1, Y: 0}, &store, collider, &collisionSys, &PhysicsWorld{}, false)
+
+		rb.SetParent(&parent)
+		collider.SetParent(&parent)
+
+		collisionSys.Mock.On("DetectContacts", mock.Anything)
+		rb.OnUpdate(10_000, nil)
+
+		require.Equal(t, testCase.Expected, parent.Pos)
+	})
+}
+
+// a 10 by 10 rigid body in a collision system, rather than in a mock
+// a collision system that colliders both register with and collide through, such as the real one or its mock
+type sceneCollisionSystem interface {
+	core.System[*collision.Collider]
+	collision.CollisionSystemMediator
+}
+
+func newRigidBodyInScene(
+	layer int,
+	name string,
+	pos util.Vec2[float32],
+	velocity util.Vec2[float32],
+	collisionSys sceneCollisionSystem,
+	world *PhysicsWorld,
+	isContinous bool,
+) (*RigidBody, *core.BaseGameObject) {
+	store := MockGameObjectStore{}
+	store.On("AddGameObject", mock.Anything)
+	parent := core.NewBaseGameObject(layer, name, pos, &store)
+	collider := collision.NewCollider(layer, name+"_collider", quadtree.Rect{X: 0, Y: 0, W: 10, H: 10}, collisionSys, collisionSys, nil, &store)
+	rb := NewRigidBody(layer, name+"_rb", velocity, &store, collider, collisionSys, world, isContinous)
+
+	// the collider follows the parent as its child
+	rb.SetParent(&parent)
+	parent.AddChild(collider)
+
+	return rb, &parent
+}
+
+func TestOnUpdateShouldRestrictMovementContinuous(t *testing.T) {
+	type TestInput struct {
+		pos      util.Vec2[float32]
+		velocity util.Vec2[float32]
+	}
+
+	type TestExpected struct {
+		pos      util.Vec2[float32]
+		velocity util.Vec2[float32]
+	}
+
+	const NAME string = "should stop at the first collider along the way with %+v"
+	getName := func(input TestInput) string {
+		return fmt.Sprintf(NAME, input)
+	}
+
+	cases := []util.TestCase[TestInput, TestExpected]{
+		// moving past the thin wall in a single update
+		{
+			Name:     getName,
+			Input:    TestInput{util.Vec2[float32]{X: 0, Y: 0}, util.Vec2[float32]{X: 1000, Y: 0}},
+			Expected: TestExpected{util.Vec2[float32]{X: 40, Y: 0}, util.Vec2[float32]{X: 0, Y: 0}},
+		},
+		{
+			Name:     getName,
+			Input:    TestInput{util.Vec2[float32]{X: 100, Y: 0}, util.Vec2[float32]{X: -1000, Y: 0}},
+			Expected: TestExpected{util.Vec2[float32]{X: 52, Y: 0}, util.Vec2[float32]{X: 0, Y: 0}},
+		},
+		// slides down the wall for the rest of the movement
+		{
+			Name:     getName,
+			Input:    TestInput{util.Vec2[float32]{X: 0, Y: 0}, util.Vec2[float32]{X: 1000, Y: 500}},
+			Expected: TestExpected{util.Vec2[float32]{X: 40, Y: 50}, util.Vec2[float32]{X: 0, Y: 500}},
+		},
+		// falling past the thin floor
+		{
+			Name:     getName,
+			Input:    TestInput{util.Vec2[float32]{X: 100, Y: 60}, util.Vec2[float32]{X: 0, Y: 2000}},
+			Expected: TestExpected{util.Vec2[float32]{X: 100, Y: 140}, util.Vec2[float32]{X: 0, Y: 0}},
+		},
+		// jumping up through the one-way platform, and landing on it
+		{
+			Name:     getName,
+			Input:    TestInput{util.Vec2[float32]{X: 200, Y: 160}, util.Vec2[float32]{X: 0, Y: -2000}},
+			Expected: TestExpected{util.Vec2[float32]{X: 200, Y: -40}, util.Vec2[float32]{X: 0, Y: -2000}},
+		},
+		{
+			Name:     getName,
+			Input:    TestInput{util.Vec2[float32]{X: 200, Y: 0}, util.Vec2[float32]{X: 0, Y: 2000}},
+			Expected: TestExpected{util.Vec2[float32]{X: 200, Y: 140}, util.Vec2[float32]{X: 0, Y: 0}},
+		},
+	}
+
+	util.IterateTestCases(cases, t, func(testCase util.TestCase[TestInput, TestExpected]) {
+		collisionSys := collision.NewCollisionSystem(quadtree.Rect{X: -500, Y: -500, W: 1000, H: 1000})
+		collision.NewCollider(core.WALL_LAYER, "wall", quadtree.Rect{X: 50, Y: -100, W: 2, H: 200}, &collisionSys, &collisionSys, nil, nil)
+		collision.NewCollider(core.WALL_LAYER, "floor", quadtree.Rect{X: 60, Y: 150, W: 100, H: 2}, &collisionSys, &collisionSys, nil, nil)
+		platform := collision.NewCollider(core.WALL_LAYER, "platform", quadtree.Rect{X: 180, Y: 150, W: 50, H: 2}, &collisionSys, &collisionSys, nil, nil)
+		platform.SetOneWay(true)
+
+		rb, parent := newRigidBodyInScene(core.PLAYER_LAYER, "player", testCase.Input.pos, testCase.Input.velocity, &collisionSys, &PhysicsWorld{}, true)
+		rb.OnUpdate(100, nil)
+
+		require.InDelta(t, testCase.Expected.pos.X, parent.Pos.X, 0.01)
+		require.InDelta(t, testCase.Expected.pos.Y, parent.Pos.Y, 0.01)
+		require.Equal(t, testCase.Expected.velocity, *rb.Velocity)
+	})
+}
+
+func TestOnUpdateShouldPassThroughThinCollidersWhenDiscrete(t *testing.T) {
+	collisionSys := collision.NewCollisionSystem(quadtree.Rect{X: -500, Y: -500, W: 1000, H: 1000})
+	collision.NewCollider(core.WALL_LAYER, "wall", quadtree.Rect{X: 50, Y: -100, W: 2, H: 200}, &collisionSys, &collisionSys, nil, nil)
+
+	rb, parent := newRigidBodyInScene(core.PLAYER_LAYER, "player", util.Vec2[float32]{X: 0, Y: 0}, util.Vec2[float32]{X: 1000, Y: 0}, &collisionSys, &PhysicsWorld{}, false)
+	rb.OnUpdate(100, nil)
+
+	require.Equal(t, util.Vec2[float32]{X: 100, Y: 0}, parent.Pos)
+}
+
+// a one-way platform whose top edge is at y = 20
+func newOneWayPlatform(collisionSys *MockCollisionSystem) *collision.Collider {
+	platform := collision.NewCollider(core.WALL_LAYER, "platform", quadtree.Rect{X: 0, Y: 20, W: 50, H: 5}, collisionSys, collisionSys, nil, nil)
+	platform.SetOneWay(true)
+
+	collisionSys.elementsToDetect = []quadtree.QuadElement{{Id: "platform", Rect: platform.Rect}}
+	collisionSys.colliders = map[string]*collision.Collider{"platform": platform}
+	collisionSys.Mock.On("DetectContacts", mock.Anything)
+
+	return platform
+}
+
+func TestOnUpdateShouldOnlyLandOnOneWayPlatformsFromAbove(t *testing.T) {
+	type TestInput struct {
+		pos      util.Vec2[float32]
+		velocity util.Vec2[float32]
+	}
+
+	const NAME string = "should move a body at %+v through a one-way platform unless landing on it"
+	getName := func(input TestInput) string {
+		return fmt.Sprintf(NAME, input)
+	}
+
+	cases := []util.TestCase[TestInput, util.Vec2[float32]]{
+		// falling on to the platform from above
+		{Name: getName, Input: TestInput{util.Vec2[float32]{X: 10, Y: 5}, util.Vec2[float32]{X: 0, Y: 1}}, Expected: util.Vec2[float32]{X: 10, Y: 10}},
+		{Name: getName, Input: TestInput{util.Vec2[float32]{X: 10, Y: 5}, util.Vec2[float32]{X: 1, Y: 1}}, Expected: util.Vec2[float32]{X: 20, Y: 10}},
+		// jumping up through the platform from below
+		{Name: getName, Input: TestInput{util.Vec2[float32]{X: 10, Y: 30}, util.Vec2[float32]{X: 0, Y: -1}}, Expected: util.Vec2[float32]{X: 10, Y: 20}},
+		// still falling after the top edge was passed
+		{Name: getName, Input: TestInput{util.Vec2[float32]{X: 10, Y: 18}, util.Vec2[float32]{X: 0, Y: 1}}, Expected: util.Vec2[float32]{X: 10, Y: 28}},
+		// walking in to the side of the platform
+		{Name: getName, Input: TestInput{util.Vec2[float32]{X: -15, Y: 18}, util.Vec2[float32]{X: 1, Y: 0}}, Expected: util.Vec2[float32]{X: -5, Y: 18}},
+	}
+
+	util.IterateTestCases(cases, t, func(testCase util.TestCase[TestInput, util.Vec2[float32]]) {
+		collisionSys := MockCollisionSystem{}
+		newOneWayPlatform(&collisionSys)
+		rb, parent := newRigidBodyInScene(core.PLAYER_LAYER, "player", testCase.Input.pos, testCase.Input.velocity, &collisionSys, &PhysicsWorld{}, false)
+
+		rb.OnUpdate(10_000, nil)
+
+		require.Equal(t, testCase.Expected, parent.Pos)
+	})
+}
+
+func TestDropThroughShouldIgnoreThePlatformBeneath(t *testing.T) {
+	collisionSys := MockCollisionSystem{}
+	newOneWayPlatform(&collisionSys)
+	rb, parent := newRigidBodyInScene(core.PLAYER_LAYER, "player", util.Vec2[float32]{X: 10, Y: 10}, util.Vec2[float32]{X: 0, Y: 100}, &collisionSys, &PhysicsWorld{}, false)
+
+	// standing on the platform
+	rb.OnUpdate(50, nil)
+	require.Equal(t, util.Vec2[float32]{X: 10, Y: 10}, parent.Pos)
+
+	rb.Velocity.Y = 100
+	rb.DropThrough(100)
+	rb.OnUpdate(50, nil)
+	require.Equal(t, util.Vec2[float32]{X: 10, Y: 15}, parent.Pos)
+
+	// the platform is no longer ignored, but the body has already fallen below its top edge
+	rb.OnUpdate(100, nil)
+	require.Empty(t, rb.ignored)
+	require.Equal(t, util.Vec2[float32]{X: 10, Y: 25}, parent.Pos)
+}
+
+func TestIgnoreColliderShouldExpire(t *testing.T) {
+	collisionSys := MockCollisionSystem{
+		elementsToDetect: []quadtree.QuadElement{{Id: "wall", Rect: quadtree.Rect{X: 20, Y: 0, W: 10, H: 10}}},
+		colliders:        make(map[string]*collision.Collider),
+	}
+	collisionSys.Mock.On("DetectContacts", mock.Anything)
+	wall := collision.NewCollider(core.WALL_LAYER, "wall", quadtree.Rect{X: 20, Y: 0, W: 10, H: 10}, &collisionSys, &collisionSys, nil, nil)
+	collisionSys.colliders["wall"] = wall
+
+	rb, parent := newRigidBodyInScene(core.PLAYER_LAYER, "player", util.Vec2[float32]{X: 0, Y: 0}, util.Vec2[float32]{X: 150, Y: 0}, &collisionSys, &PhysicsWorld{}, false)
+	rb.IgnoreCollider(wall, 100)
+
+	rb.OnUpdate(100, nil)
+	require.Equal(t, util.Vec2[float32]{X: 15, Y: 0}, parent.Pos)
+
+	// once the wall is no longer ignored the body is pushed back out of it
+	rb.OnUpdate(100, nil)
+	require.Equal(t, util.Vec2[float32]{X: 10, Y: 0}, parent.Pos)
+}
+
+func TestOnUpdateShouldKeepBodiesOnSlopes(t *testing.T) {
+	type TestInput struct {
+		angle       float64
+		startX      float32
+		velocity    util.Vec2[float32]
+		isContinous bool
+	}
+
+	const NAME string = "should walk over the slope with %+v"
+	getName := func(input TestInput) string {
+		return fmt.Sprintf(NAME, input)
+	}
+
+	cases := []util.TestCase[TestInput, any]{
+		// up the slope from the floor on to the top
+		{Name: getName, Input: TestInput{collision.SLOPE_45, 60, util.Vec2[float32]{X: 100, Y: 0}, false}},
+		{Name: getName, Input: TestInput{collision.SLOPE_22_5, 60, util.Vec2[float32]{X: 100, Y: 0}, false}},
+		{Name: getName, Input: TestInput{collision.SLOPE_45, 60, util.Vec2[float32]{X: 100, Y: 0}, true}},
+		// down the slope from the top on to the floor
+		{Name: getName, Input: TestInput{collision.SLOPE_45, 180, util.Vec2[float32]{X: -100, Y: 0}, false}},
+		{Name: getName, Input: TestInput{collision.SLOPE_22_5, 180, util.Vec2[float32]{X: -100, Y: 0}, false}},
+		{Name: getName, Input: TestInput{collision.SLOPE_45, 180, util.Vec2[float32]{X: -100, Y: 0}, true}},
+	}
+
+	util.IterateTestCases(cases, t, func(testCase util.TestCase[TestInput, any]) {
+		// a floor up to x = 100, then a slope rising to the right up to a top starting at x = 150
+		rise := 50 * float32(math.Tan(testCase.Input.angle))
+		collisionSys := collision.NewCollisionSystem(quadtree.Rect{X: 0, Y: -100, W: 300, H: 300})
+		collision.NewCollider(core.WALL_LAYER, "floor", quadtree.Rect{X: 0, Y: 100, W: 100, H: 10}, &collisionSys, &collisionSys, nil, nil)
+		collision.NewShapeCollider(
+			core.WALL_LAYER,
+			"slope",
+			util.Vec2[float32]{X: 100, Y: 100 - rise},
+			collision.NewSlope(50, testCase.Input.angle, true),
+			&collisionSys,
+			&collisionSys,
+			nil,
+			nil,
+		)
+		collision.NewCollider(core.WALL_LAYER, "top", quadtree.Rect{X: 150, Y: 100 - rise, W: 100, H: 10}, &collisionSys, &collisionSys, nil, nil)
+
+		// the height of the ground under a body, which is highest at its right edge as the ground only rises to the right
+		groundUnder := func(right float32) float32 {
+			return 100 - min(max(right-100, 0), 50)*rise/50
+		}
+
+		start := util.Vec2[float32]{X: testCase.Input.startX, Y: groundUnder(testCase.Input.startX+10) - 10}
+		rb, parent := newRigidBodyInScene(core.PLAYER_LAYER, "player", start, testCase.Input.velocity, &collisionSys, &PhysicsWorld{}, testCase.Input.isContinous)
+
+		for i := 1; i <= 60; i++ {
+			rb.OnUpdate(16, nil)
+
+			// keeps its speed and stays on the ground, without bouncing off it or sinking in to it
+			require.Equal(t, testCase.Input.velocity, *rb.Velocity)
+			require.InDelta(t, start.X+float32(i)*testCase.Input.velocity.X*0.016, parent.Pos.X, 0.01)
+			require.InDelta(t, groundUnder(parent.Pos.X+10), parent.Pos.Y+10, 0.1, "step %d at %+v", i, parent.Pos)
+			require.True(t, rb.IsGrounded(), "step %d at %+v", i, parent.Pos)
+		}
+	})
+}
+
+func TestOnUpdateShouldStopAtThinWallsWhenRunningContinuous(t *testing.T) {
+	collisionSys := collision.NewCollisionSystem(quadtree.Rect{X: -500, Y: -500, W: 1000, H: 1000})
+	collision.NewCollider(core.WALL_LAYER, "floor", quadtree.Rect{X: -100, Y: 150, W: 300, H: 10}, &collisionSys, &collisionSys, nil, nil)
+	collision.NewCollider(core.WALL_LAYER, "wall", quadtree.Rect{X: 50, Y: 0, W: 2, H: 150}, &collisionSys, &collisionSys, nil, nil)
+
+	rb, parent := newRigidBodyInScene(core.PLAYER_LAYER, "player", util.Vec2[float32]{X: -50, Y: 140}, util.Vec2[float32]{X: 100, Y: 0}, &collisionSys, &PhysicsWorld{}, true)
+	rb.OnUpdate(100, nil)
+	require.True(t, rb.IsGrounded())
+
+	rb.Velocity.X = 2000
+	rb.OnUpdate(100, nil)
+	require.InDelta(t, 40, parent.Pos.X, 0.01)
+	require.Equal(t, float32(140), parent.Pos.Y)
+	require.True(t, rb.IsGrounded())
+}
+
+func TestOnUpdateShouldLandOnSlopesAtShallowAnglesWhenContinuous(t *testing.T) {
+	const NAME string = "should land on the slope and slide down it when falling %v pixels for every pixel moved across"
+	getName := func(input float32) string {
+		return fmt.Sprintf(NAME, input)
+	}
+
+	// the 22.5 degree slope falls 0.414 pixels for every pixel moved across, so the body closes in on it slowly,
+	// landing 117 and 279 pixels along the move, and slides the rest of the way down it
+	cases := []util.TestCase[float32, float32]{
+		{Name: getName, Input: 0.5, Expected: 74.44},
+		{Name: getName, Input: 0.45, Expected: 79.74},
+	}
+
+	util.IterateTestCases(cases, t, func(testCase util.TestCase[float32, float32]) {
+		collisionSys := collision.NewCollisionSystem(quadtree.Rect{X: -500, Y: -500, W: 1000, H: 1000})
+		collision.NewShapeCollider(
+			core.WALL_LAYER,
+			"slope",
+			util.Vec2[float32]{X: 0, Y: 0},
+			collision.NewSlope(400, collision.SLOPE_22_5, true),
+			&collisionSys,
+			&collisionSys,
+			nil,
+			nil,
+		)
+
+		rise := float32(math.Tan(collision.SLOPE_22_5))
+		surfaceAt := func(x float32) float32 {
+			return (400 - x) * rise
+		}
+
+		// the bottom right corner of the body starts 10 above the slope, and moves 300 across it in one update
+		start := util.Vec2[float32]{X: 380, Y: surfaceAt(390) - 20}
+		velocity := util.Vec2[float32]{X: -3000, Y: 3000 * testCase.Input}
+		rb, parent := newRigidBodyInScene(core.PLAYER_LAYER, "player", start, velocity, &collisionSys, &PhysicsWorld{}, true)
+		rb.OnUpdate(100, nil)
+
+		require.InDelta(t, testCase.Expected, parent.Pos.X, 0.01)
+		require.InDelta(t, surfaceAt(parent.Pos.X+10), parent.Pos.Y+10, 0.01)
+		require.True(t, rb.IsGrounded())
+
+		// only the velocity along the slope is kept
+		down := util.Vec2[float32]{X: -float32(math.Cos(collision.SLOPE_22_5)), Y: float32(math.Sin(collision.SLOPE_22_5))}
+		require.InDelta(t, velocity.Dot(down)*down.X, rb.Velocity.X, 0.1)
+		require.InDelta(t, velocity.Dot(down)*down.Y, rb.Velocity.Y, 0.1)
+	})
+}
+
+func TestOnUpdateShouldIntegrateForces(t *testing.T) {
+	type TestInput struct {
+		velocity     util.Vec2[float32]
+		gravity      util.Vec2[float32]
+		gravityScale float32
+		mass         float32
+		drag         float32
+		maxFallSpeed float32
+		force        util.Vec2[float32]
+		impulse      util.Vec2[float32]
+	}
+
+	type TestExpected struct {
+		velocity util.Vec2[float32]
+		pos      util.Vec2[float32]
+	}
+
+	const NAME string = "should integrate %+v"
+	getName := func(input TestInput) string {
+		return fmt.Sprintf(NAME, input)
+	}
+
+	noLimit := float32(math.Inf(1))
+	down := util.Vec2[float32]{X: 0, Y: 1000}
+
+	cases := []util.TestCase[TestInput, TestExpected]{
+		// the velocity is updated before the position
+		{
+			Name:     getName,
+			Input:    TestInput{gravity: down, gravityScale: 1, mass: 1, maxFallSpeed: noLimit},
+			Expected: TestExpected{util.Vec2[float32]{X: 0, Y: 100}, util.Vec2[float32]{X: 0, Y: 10}},
+		},
+		{
+			Name:     getName,
+			Input:    TestInput{gravity: down, gravityScale: 0.5, mass: 4, maxFallSpeed: noLimit},
+			Expected: TestExpected{util.Vec2[float32]{X: 0, Y: 50}, util.Vec2[float32]{X: 0, Y: 5}},
+		},
+		{
+			Name:     getName,
+			Input:    TestInput{velocity: util.Vec2[float32]{X: 100, Y: 0}, gravity: down, gravityScale: 0, mass: 1, maxFallSpeed: noLimit},
+			Expected: TestExpected{util.Vec2[float32]{X: 100, Y: 0}, util.Vec2[float32]{X: 10, Y: 0}},
+		},
+		// forces are divided by the mass
+		{
+			Name:     getName,
+			Input:    TestInput{gravityScale: 1, mass: 2, maxFallSpeed: noLimit, force: util.Vec2[float32]{X: 200, Y: 0}},
+			Expected: TestExpected{util.Vec2[float32]{X: 10, Y: 0}, util.Vec2[float32]{X: 1, Y: 0}},
+		},
+		{
+			Name:     getName,
+			Input:    TestInput{gravity: down, gravityScale: 1, mass: 2, maxFallSpeed: noLimit, impulse: util.Vec2[float32]{X: 0, Y: -300}},
+			Expected: TestExpected{util.Vec2[float32]{X: 0, Y: -50}, util.Vec2[float32]{X: 0, Y: -5}},
+		},
+		{
+			Name:     getName,
+			Input:    TestInput{velocity: util.Vec2[float32]{X: 110, Y: -110}, gravityScale: 1, mass: 1, drag: 1, maxFallSpeed: noLimit},
+			Expected: TestExpected{util.Vec2[float32]{X: 100, Y: -100}, util.Vec2[float32]{X: 10, Y: -10}},
+		},
+		{
+			Name:     getName,
+			Input:    TestInput{velocity: util.Vec2[float32]{X: 0, Y: 40}, gravity: down, gravityScale: 1, mass: 1, maxFallSpeed: 50},
+			Expected: TestExpected{util.Vec2[float32]{X: 0, Y: 50}, util.Vec2[float32]{X: 0, Y: 5}},
+		},
+	}
+
+	util.IterateTestCases(cases, t, func(testCase util.TestCase[TestInput, TestExpected]) {
+		collisionSys := MockCollisionSystem{}
+		collisionSys.Mock.On("DetectContacts", mock.Anything)
+		world := PhysicsWorld{Gravity: testCase.Input.gravity}
+
+		rb, parent := newRigidBodyInScene(core.PLAYER_LAYER, "player", util.Vec2[float32]{}, testCase.Input.velocity, &collisionSys, &world, false)
+		rb.SetGravityScale(testCase.Input.gravityScale)
+		rb.SetMass(testCase.Input.mass)
+		rb.SetDrag(testCase.Input.drag)
+		rb.SetMaxFallSpeed(testCase.Input.maxFallSpeed)
+		rb.AddForce(testCase.Input.force)
+		rb.AddImpulse(testCase.Input.impulse)
+
+		rb.OnUpdate(100, nil)
+
+		require.InDelta(t, testCase.Expected.velocity.X, rb.Velocity.X, 1e-4)
+		require.InDelta(t, testCase.Expected.velocity.Y, rb.Velocity.Y, 1e-4)
+		require.InDelta(t, testCase.Expected.pos.X, parent.Pos.X, 1e-4)
+		require.InDelta(t, testCase.Expected.pos.Y, parent.Pos.Y, 1e-4)
+	})
+}
+
+func TestAddForceShouldOnlyPushForOneUpdate(t *testing.T) {
+	collisionSys := MockCollisionSystem{}
+	collisionSys.Mock.On("DetectContacts", mock.Anything)
+	rb, parent := newRigidBodyInScene(core.PLAYER_LAYER, "player", util.Vec2[float32]{}, util.Vec2[float32]{}, &collisionSys, &PhysicsWorld{}, false)
+
+	rb.AddForce(util.Vec2[float32]{X: 50, Y: 0})
+	rb.AddForce(util.Vec2[float32]{X: 50, Y: 0})
+	rb.OnUpdate(100, nil)
+	require.Equal(t, util.Vec2[float32]{X: 10, Y: 0}, *rb.Velocity)
+
+	rb.OnUpdate(100, nil)
+	require.Equal(t, util.Vec2[float32]{X: 10, Y: 0}, *rb.Velocity)
+	require.InDelta(t, 2, parent.Pos.X, 1e-5)
+}
+
+func TestRigidBodySettersShouldPanic(t *testing.T) {
+	collisionSys := MockCollisionSystem{}
+	rb, _ := newRigidBodyInScene(core.PLAYER_LAYER, "player", util.Vec2[float32]{}, util.Vec2[float32]{}, &collisionSys, &PhysicsWorld{}, false)
+
+	require.PanicsWithValue(t, "the mass of a rigid body must be positive", func() {
+		rb.SetMass(0)
+	})
+	require.PanicsWithValue(t, "the drag of a rigid body cannot be negative", func() {
+		rb.SetDrag(-1)
+	})
+	require.PanicsWithValue(t, "the restitution of a rigid body must be between 0 and 1", func() {
+		rb.SetRestitution(1.5)
+	})
+	require.PanicsWithValue(t, "the friction of a rigid body cannot be negative", func() {
+		rb.SetFriction(-1)
+	})
+}
+
+func TestNewRigidBodyShouldPanicWithoutAWorld(t *testing.T) {
+	collisionSys := MockCollisionSystem{}
+	collider := collision.NewCollider(core.PLAYER_LAYER, "rb_collider", quadtree.Rect{X: 0, Y: 0, W: 10, H: 10}, &collisionSys, &collisionSys, nil, nil)
+
+	require.PanicsWithValue(t, "a rigid body needs a physics world", func() {
+		NewRigidBody(core.PLAYER_LAYER, "rigidbody", util.Vec2[float32]{}, nil, collider, &collisionSys, nil, false)
+	})
+}
+
+func TestOnUpdateShouldPushOtherRigidBodies(t *testing.T) {
+	type TestInput struct {
+		velocity    util.Vec2[float32]
+		crateMass   float32
+		restitution float32
+		friction    float32
+		isWalled    bool // whether there is a wall behind the crate
+	}
+
+	type TestExpected struct {
+		pos           util.Vec2[float32]
+		velocity      util.Vec2[float32]
+		cratePos      util.Vec2[float32]
+		crateVelocity util.Vec2[float32]
+	}
+
+	const NAME string = "should push the crate with %+v"
+	getName := func(input TestInput) string {
+		return fmt.Sprintf(NAME, input)
+	}
+
+	right := util.Vec2[float32]{X: 100, Y: 0}
+
+	cases := []util.TestCase[TestInput, TestExpected]{
+		// the overlap and the velocity are shared by the masses of the bodies
+		{
+			Name:     getName,
+			Input:    TestInput{right, 1, 0, 0, false},
+			Expected: TestExpected{util.Vec2[float32]{X: 5, Y: 0}, util.Vec2[float32]{X: 50, Y: 0}, util.Vec2[float32]{X: 15, Y: 0}, util.Vec2[float32]{X: 50, Y: 0}},
+		},
+		{
+			Name:     getName,
+			Input:    TestInput{right, 3, 0, 0, false},
+			Expected: TestExpected{util.Vec2[float32]{X: 2.5, Y: 0}, util.Vec2[float32]{X: 25, Y: 0}, util.Vec2[float32]{X: 12.5, Y: 0}, util.Vec2[float32]{X: 25, Y: 0}},
+		},
+		// bouncing off
+		{
+			Name:     getName,
+			Input:    TestInput{right, 1, 1, 0, false},
+			Expected: TestExpected{util.Vec2[float32]{X: 5, Y: 0}, util.Vec2[float32]{X: 0, Y: 0}, util.Vec2[float32]{X: 15, Y: 0}, util.Vec2[float32]{X: 100, Y: 0}},
+		},
+		// the crate cannot be pushed in to the wall
+		{
+			Name:     getName,
+			Input:    TestInput{right, 1, 0, 0, true},
+			Expected: TestExpected{util.Vec2[float32]{X: 0, Y: 0}, util.Vec2[float32]{X: 0, Y: 0}, util.Vec2[float32]{X: 10, Y: 0}, util.Vec2[float32]{X: 0, Y: 0}},
+		},
+		// friction drags the crate along, but at most stops the bodies sliding along each other
+		{
+			Name:     getName,
+			Input:    TestInput{util.Vec2[float32]{X: 100, Y: 50}, 1, 0, 1, false},
+			Expected: TestExpected{util.Vec2[float32]{X: 5, Y: 5}, util.Vec2[float32]{X: 50, Y: 25}, util.Vec2[float32]{X: 15, Y: 0}, util.Vec2[float32]{X: 50, Y: 25}},
+		},
+		{
+			Name:     getName,
+			Input:    TestInput{util.Vec2[float32]{X: 100, Y: 50}, 1, 0, 0.2, false},
+			Expected: TestExpected{util.Vec2[float32]{X: 5, Y: 5}, util.Vec2[float32]{X: 50, Y: 40}, util.Vec2[float32]{X: 15, Y: 0}, util.Vec2[float32]{X: 50, Y: 10}},
+		},
+	}
+
+	util.IterateTestCases(cases, t, func(testCase util.TestCase[TestInput, TestExpected]) {
+		collisionSys := collision.NewCollisionSystem(quadtree.Rect{X: -500, Y: -500, W: 1000, H: 1000})
+		if testCase.Input.isWalled {
+			collision.NewCollider(core.WALL_LAYER, "wall", quadtree.Rect{X: 20, Y: -50, W: 10, H: 100}, &collisionSys, &collisionSys, nil, nil)
+		}
+
+		rb, parent := newRigidBodyInScene(core.PLAYER_LAYER, "player", util.Vec2[float32]{}, testCase.Input.velocity, &collisionSys, &PhysicsWorld{}, false)
+		rb.SetRestitution(testCase.Input.restitution)
+		rb.SetFriction(testCase.Input.friction)
+
+		crate, crateParent := newRigidBodyInScene(core.ENEMY_LAYER, "crate", util.Vec2[float32]{X: 10, Y: 0}, util.Vec2[float32]{}, &collisionSys, &PhysicsWorld{}, false)
+		crate.SetMass(testCase.Input.crateMass)
+		crate.SetFriction(testCase.Input.friction)
+
+		rb.OnUpdate(100, nil)
+
+		require.InDelta(t, testCase.Expected.pos.X, parent.Pos.X, 1e-4)
+		require.InDelta(t, testCase.Expected.pos.Y, parent.Pos.Y, 1e-4)
+		require.InDelta(t, testCase.Expected.velocity.X, rb.Velocity.X, 1e-4)
+		require.InDelta(t, testCase.Expected.velocity.Y, rb.Velocity.Y, 1e-4)
+		require.InDelta(t, testCase.Expected.cratePos.X, crateParent.Pos.X, 1e-4)
+		require.InDelta(t, testCase.Expected.cratePos.Y, crateParent.Pos.Y, 1e-4)
+		require.InDelta(t, testCase.Expected.crateVelocity.X, crate.Velocity.X, 1e-4)
+		require.InDelta(t, testCase.Expected.crateVelocity.Y, crate.Velocity.Y, 1e-4)
+	})
+}
+
+func TestOnUpdateShouldResolveEveryContact(t *testing.T) {
+	type TestInput struct {
+		pos      util.Vec2[float32]
+		velocity util.Vec2[float32]
+	}
+
+	type TestExpected struct {
+		pos       util.Vec2[float32]
+		grounded  bool
+		wallLeft  bool
+		wallRight bool
+		ceiling   bool
+	}
+
+	const NAME string = "should touch the sides of the room with %+v"
+	getName := func(input TestInput) string {
+		return fmt.Sprintf(NAME, input)
+	}
+
+	cases := []util.TestCase[TestInput, TestExpected]{
+		{
+			Name:     getName,
+			Input:    TestInput{util.Vec2[float32]{X: 20, Y: 90}, util.Vec2[float32]{X: 0, Y: 0}},
+			Expected: TestExpected{pos: util.Vec2[float32]{X: 20, Y: 90}, grounded: true},
+		},
+		// pushing in to the corners of the floor
+		{
+			Name:     getName,
+			Input:    TestInput{util.Vec2[float32]{X: 40, Y: 90}, util.Vec2[float32]{X: 100, Y: 0}},
+			Expected: TestExpected{pos: util.Vec2[float32]{X: 40, Y: 90}, grounded: true, wallRight: true},
+		},
+		{
+			Name:     getName,
+			Input:    TestInput{util.Vec2[float32]{X: 0, Y: 90}, util.Vec2[float32]{X: -100, Y: 0}},
+			Expected: TestExpected{pos: util.Vec2[float32]{X: 0, Y: 90}, grounded: true, wallLeft: true},
+		},
+		// falling while pushing in to a wall slides down it
+		{
+			Name:     getName,
+			Input:    TestInput{util.Vec2[float32]{X: 40, Y: 40}, util.Vec2[float32]{X: 100, Y: 100}},
+			Expected: TestExpected{pos: util.Vec2[float32]{X: 40, Y: 60}, wallRight: true},
+		},
+		// jumping in to the ceiling, and in to its corner
+		{
+			Name:     getName,
+			Input:    TestInput{util.Vec2[float32]{X: 20, Y: 5}, util.Vec2[float32]{X: 0, Y: -200}},
+			Expected: TestExpected{pos: util.Vec2[float32]{X: 20, Y: 0}, ceiling: true},
+		},
+		{
+			Name:     getName,
+			Input:    TestInput{util.Vec2[float32]{X: 5, Y: 5}, util.Vec2[float32]{X: -200, Y: -200}},
+			Expected: TestExpected{pos: util.Vec2[float32]{X: 0, Y: 0}, ceiling: true, wallLeft: true},
+		},
+		// in the middle of the room
+		{
+			Name:     getName,
+			Input:    TestInput{util.Vec2[float32]{X: 20, Y: 40}, util.Vec2[float32]{X: 100, Y: 0}},
+			Expected: TestExpected{pos: util.Vec2[float32]{X: 30, Y: 50}},
+		},
+	}
+
+	util.IterateTestCases(cases, t, func(testCase util.TestCase[TestInput, TestExpected]) {
+		// a room from 0, 0 to 50, 100
+		collisionSys := collision.NewCollisionSystem(quadtree.Rect{X: -500, Y: -500, W: 1000, H: 1000})
+		world := PhysicsWorld{Gravity: util.Vec2[float32]{X: 0, Y: 1000}}
+		collision.NewCollider(core.WALL_LAYER, "floor", quadtree.Rect{X: -100, Y: 100, W: 300, H: 10}, &collisionSys, &collisionSys, nil, nil)
+		collision.NewCollider(core.WALL_LAYER, "ceiling", quadtree.Rect{X: -100, Y: -10, W: 300, H: 10}, &collisionSys, &collisionSys, nil, nil)
+		collision.NewCollider(core.WALL_LAYER, "left", quadtree.Rect{X: -10, Y: -100, W: 10, H: 300}, &collisionSys, &collisionSys, nil, nil)
+		collision.NewCollider(core.WALL_LAYER, "right", quadtree.Rect{X: 50, Y: -100, W: 10, H: 300}, &collisionSys, &collisionSys, nil, nil)
+
+		rb, parent := newRigidBodyInScene(core.PLAYER_LAYER, "player", testCase.Input.pos, testCase.Input.velocity, &collisionSys, &world, false)
+		rb.OnUpdate(100, nil)
+
+		require.InDelta(t, testCase.Expected.pos.X, parent.Pos.X, 1e-4)
+		require.InDelta(t, testCase.Expected.pos.Y, parent.Pos.Y, 1e-4)
+		require.Equal(t, testCase.Expected.grounded, rb.IsGrounded())
+		require.Equal(t, testCase.Expected.wallLeft, rb.IsOnWallLeft())
+		require.Equal(t, testCase.Expected.wallRight, rb.IsOnWallRight())
+		require.Equal(t, testCase.Expected.ceiling, rb.IsOnCeiling())
+	})
+}
+
+func TestOnUpdateShouldResolveContactsThatPushIntoEachOther(t *testing.T) {
+	// a V between two slopes too steep to stand on, which push a body falling in to them in to each other
+	collisionSys := collision.NewCollisionSystem(quadtree.Rect{X: -500, Y: -500, W: 1000, H: 1000})
+	left := collision.NewShapeCollider(
+		core.WALL_LAYER, "left", util.Vec2[float32]{X: 0, Y: 0}, collision.NewSlope(20, math.Pi/3, false), &collisionSys, &collisionSys, nil, nil,
+	)
+	right := collision.NewShapeCollider(
+		core.WALL_LAYER, "right", util.Vec2[float32]{X: 20, Y: 0}, collision.NewSlope(20, math.Pi/3, true), &collisionSys, &collisionSys, nil, nil,
+	)
+
+	rb, _ := newRigidBodyInScene(core.PLAYER_LAYER, "player", util.Vec2[float32]{X: 15, Y: 0}, util.Vec2[float32]{X: 0, Y: 300}, &collisionSys, &PhysicsWorld{}, false)
+	rb.OnUpdate(100, nil)
+
+	require.Less(t, rb.collider.ContactWith(left).Depth, float32(0.1))
+	require.Less(t, rb.collider.ContactWith(right).Depth, float32(0.1))
+}
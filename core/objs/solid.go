@@ -3,30 +3,36 @@ package objs
 import (
 	"github.com/TheRaizer/GolangGame/core"
 	"github.com/TheRaizer/GolangGame/util"
+	"github.com/TheRaizer/GolangGame/util/datastructures/quadtree"
 	"github.com/veandco/go-sdl2/sdl"
 )
 
 type Solid struct {
 	core.BaseGameObject
 
-	rect  *sdl.Rect
+	rect  quadtree.Rect
 	pixel uint32
 }
 
 var colour = sdl.Color{R: 128, G: 128, B: 128, A: 255}
 
-func NewSolid(name string, initPos util.Vec2[float32], gameObjectStore core.GameObjectStore, width int32, height int32) Solid {
+func NewSolid(name string, initPos util.Vec2[float32], gameObjectStore core.GameObjectStore, width float32, height float32) Solid {
 	return Solid{
 		BaseGameObject: core.NewBaseGameObject(core.WALL_LAYER, name, initPos, gameObjectStore),
-		rect:           &sdl.Rect{X: int32(initPos.X), Y: int32(initPos.Y), W: width, H: height},
+		rect:           quadtree.Rect{X: initPos.X, Y: initPos.Y, W: width, H: height},
 	}
 }
 
 func (wall *Solid) OnInit(surface *sdl.Surface, _ *sdl.Renderer) {
 	wall.pixel = sdl.MapRGBA(surface.Format, colour.R, colour.G, colour.B, colour.A)
-	surface.FillRect(wall.rect, wall.pixel)
+	wall.fill(surface)
 }
 
 func (wall *Solid) OnUpdate(dt uint64, surface *sdl.Surface) {
-	surface.FillRect(wall.rect, wall.pixel)
+	wall.fill(surface)
+}
+
+func (wall *Solid) fill(surface *sdl.Surface) {
+	rect := wall.rect.ToSDL()
+	surface.FillRect(&rect, wall.pixel)
 }
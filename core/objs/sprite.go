@@ -5,6 +5,7 @@ import (
 
 	"github.com/TheRaizer/GolangGame/core"
 	"github.com/TheRaizer/GolangGame/util"
+	"github.com/TheRaizer/GolangGame/util/datastructures/quadtree"
 	"github.com/veandco/go-sdl2/sdl"
 )
 
@@ -15,10 +16,10 @@ type Sprite struct {
 	pixels [][][3]uint8 // a matrix of RGB values
 }
 
-func NewSprite(name string, initPos util.Vec2[float32], gameObjectStore core.GameObjectStore, width int32, height int32) Solid {
+func NewSprite(name string, initPos util.Vec2[float32], gameObjectStore core.GameObjectStore, width float32, height float32) Solid {
 	return Solid{
 		BaseGameObject: core.NewBaseGameObject(core.WALL_LAYER, name, initPos, gameObjectStore),
-		rect:           &sdl.Rect{X: int32(initPos.X), Y: int32(initPos.Y), W: width, H: height},
+		rect:           quadtree.Rect{X: initPos.X, Y: initPos.Y, W: width, H: height},
 	}
 }
 
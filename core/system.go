@@ -5,3 +5,8 @@ type System[T GameObject] interface {
 	DeregisterObject(obj T)
 	OnLoop()
 }
+
+// Steps what it simulates forward by the milliseconds, after every game object has been updated by them
+type Simulation interface {
+	Step(dt uint64)
+}
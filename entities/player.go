@@ -4,64 +4,97 @@ import (
 	"github.com/TheRaizer/GolangGame/core"
 	"github.com/TheRaizer/GolangGame/core/objs"
 	"github.com/TheRaizer/GolangGame/util"
+	"github.com/TheRaizer/GolangGame/util/datastructures/quadtree"
 	"github.com/veandco/go-sdl2/sdl"
 )
 
 type Player struct {
 	core.BaseGameObject
 
-	rect    *sdl.Rect
-	pixel   uint32
-	rb      *objs.RigidBody
-	surface *sdl.Surface
-	speed   float32
+	rect        quadtree.Rect
+	pixel       uint32
+	controller  objs.CharacterController
+	surface     *sdl.Surface
+	isLeftHeld  bool
+	isRightHeld bool
 }
 
 var colour = sdl.Color{R: 255, G: 0, B: 255, A: 255} // purple
 
-func NewPlayer(name string, initPos util.Vec2[float32], speed float32, gameObjectStore core.GameObjectStore, rb *objs.RigidBody) Player {
+// how many milliseconds the player ignores the one-way platform it drops through
+const dropThroughTime uint64 = 250
+
+// Creates a player moved by the rigid body, which the player updates, so it should not be added to the game itself
+func NewPlayer(
+	name string,
+	initPos util.Vec2[float32],
+	config objs.CharacterConfig,
+	gameObjectStore core.GameObjectStore,
+	rb *objs.RigidBody,
+) Player {
 	return Player{
 		BaseGameObject: core.NewBaseGameObject(core.PLAYER_LAYER, name, initPos, gameObjectStore),
-		rb:             rb,
-		speed:          speed,
+		controller:     objs.NewCharacterController(rb, config),
 	}
 }
 
 func (player *Player) OnInit(surface *sdl.Surface, renderer *sdl.Renderer) {
 	player.surface = surface
-	player.rect = &sdl.Rect{X: int32(player.Pos.X), Y: int32(player.Pos.Y), W: 32, H: 32}
+	player.rect = quadtree.Rect{X: player.Pos.X, Y: player.Pos.Y, W: 32, H: 32}
 
 	player.pixel = sdl.MapRGBA(surface.Format, colour.R, colour.G, colour.B, colour.A)
-	surface.FillRect(player.rect, player.pixel)
+	player.fill(player.pixel)
 }
 
 func (player *Player) UpdatePos(distX float32, distY float32) {
-	player.surface.FillRect(player.rect, 0)
+	player.fill(0)
 
 	player.BaseGameObject.UpdatePos(distX, distY)
-	player.rect.X = int32(player.Pos.X)
-	player.rect.Y = int32(player.Pos.Y)
+	player.rect.X = player.Pos.X
+	player.rect.Y = player.Pos.Y
+
+	player.fill(player.pixel)
+}
 
-	player.surface.FillRect(player.rect, player.pixel)
+func (player *Player) fill(pixel uint32) {
+	rect := player.rect.ToSDL()
+	player.surface.FillRect(&rect, pixel)
 }
 
 func (player *Player) OnUpdate(dt uint64, surface *sdl.Surface) {
+	player.controller.OnUpdate(dt, surface)
 }
 
 func (player *Player) OnInput(event sdl.Event) {
 	switch t := event.(type) {
 	case *sdl.KeyboardEvent:
-		if t.State == sdl.PRESSED {
-			if t.Keysym.Sym == sdl.K_a {
-				player.rb.Velocity.X = -1 * player.speed
-			} else if t.Keysym.Sym == sdl.K_d {
-				player.rb.Velocity.X = 1 * player.speed
+		isPressed := t.State == sdl.PRESSED
+
+		switch t.Keysym.Sym {
+		case sdl.K_a:
+			player.isLeftHeld = isPressed
+		case sdl.K_d:
+			player.isRightHeld = isPressed
+		case sdl.K_w, sdl.K_SPACE:
+			if !isPressed {
+				player.controller.ReleaseJump()
+			} else if t.Repeat == 0 {
+				player.controller.PressJump()
 			}
-		} else if t.State == sdl.RELEASED {
-			if (t.Keysym.Sym == sdl.K_a && player.rb.Velocity.X < 0) || (t.Keysym.Sym == sdl.K_d && player.rb.Velocity.X > 0) {
-				player.rb.Velocity.X = 0
+		case sdl.K_s:
+			if isPressed {
+				player.controller.DropThrough(dropThroughTime)
 			}
 		}
+
+		var direction float32
+		if player.isLeftHeld {
+			direction--
+		}
+		if player.isRightHeld {
+			direction++
+		}
+		player.controller.SetMove(direction)
 		break
 	}
 }
@@ -25,6 +25,7 @@ func main() {
 
 	// globalRect := quadtree.Rect{X: 0, Y: 0, W: display.WIDTH, H: display.HEIGHT}
 	// collisionSys := collision.NewCollisionSystem(globalRect)
+	// world := objs.PhysicsWorld{Gravity: util.Vec2[float32]{X: 0, Y: 1500}}
 	//
 	// // generate a gray image
 	// img := image.NewGray(image.Rectangle{Max: image.Point{X: display.WIDTH, Y: display.HEIGHT}})
@@ -40,39 +41,15 @@ func main() {
 	// 	&game,
 	// )
 	//
-	// rb := objs.NewRigidBody(core.PLAYER_LAYER, "player_rb", util.Vec2[float32]{}, &game, playerCollider, &collisionSys, true)
-	// player := entities.NewPlayer("player", util.Vec2[float32]{X: 0, Y: 0}, 200, &game, &rb)
-	//
-	// playerFloorCollider := collision.NewCollider(
-	// 	core.PLAYER_LAYER,
-	// 	"player_floor_collider",
-	// 	quadtree.Rect{X: 2, Y: 32, W: 30, H: 3}, // at the bottom of the player but not quite the entire width
-	// 	&collisionSys,
-	// 	&collisionSys,
-	// 	[]func(els []quadtree.QuadElement){
-	// 		func(els []quadtree.QuadElement) {
-	// 			if len(els) <= 1 {
-	// 				player.CanJump = false
-	// 				return
-	// 			}
-	//
-	// 			for _, el := range els {
-	// 				obj := game.GetGameObject(el.Id)
-	// 				// if colliding with something not the player, then allow a jump
-	// 				if obj.Layer() != core.PLAYER_LAYER && rb.Velocity.Y > 0 {
-	// 					player.CanJump = true }
-	// 			}
-	// 		},
-	// 	},
-	// 	&game,
-	// )
+	// rb := objs.NewRigidBody(core.PLAYER_LAYER, "player_rb", util.Vec2[float32]{}, &game, playerCollider, &collisionSys, &world, true)
+	// player := entities.NewPlayer("player", util.Vec2[float32]{X: 0, Y: 0}, objs.DefaultCharacterConfig(), &game, rb)
 	//
 	// player.AddChild(playerCollider)
-	// player.AddChild(playerFloorCollider)
-	// player.AddChild(&rb)
+	// // the player updates its rigid body, so the rigid body is not added to the game
+	// rb.SetParent(&player)
 	//
-	// var wallWidth int32 = 300
-	// var wallHeight int32 = 32
+	// var wallWidth float32 = 300
+	// var wallHeight float32 = 32
 	// floor := objs.NewSolid("floor_1", util.Vec2[float32]{X: 0, Y: 500}, &game, wallWidth, wallHeight)
 	// floor.AddChild(collision.NewCollider(
 	// 	core.WALL_LAYER,
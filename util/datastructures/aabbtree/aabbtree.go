@@ -0,0 +1,257 @@
+package aabbtree
+
+import (
+	"github.com/TheRaizer/GolangGame/util/datastructures/quadtree"
+)
+
+// https://box2d.org/files/ErinCatto_DynamicBVH_Full.pdf
+
+// A dynamic bounding volume hierarchy where every leaf holds a single element and every
+// branch holds the rect bounding its two children. The tree is kept balanced with rotations.
+type AABBTree struct {
+	root   *treeNode
+	leaves map[string]*treeNode // leaf of each element by id so removal does not need to search
+}
+
+type treeNode struct {
+	rect   quadtree.Rect // the element rect for a leaf, otherwise the rect bounding both children
+	height int           // 0 for leaves
+	parent *treeNode
+	left   *treeNode
+	right  *treeNode
+	el     quadtree.QuadElement // only set on leaves
+}
+
+func NewAABBTree() AABBTree {
+	return AABBTree{
+		leaves: make(map[string]*treeNode),
+	}
+}
+
+func (node *treeNode) isLeaf() bool {
+	return node.left == nil
+}
+
+// Inserts an element as a sibling of the node where it adds the least perimeter to the tree
+func (tree *AABBTree) Insert(el quadtree.QuadElement) {
+	leaf := &treeNode{rect: el.Rect, el: el}
+	tree.leaves[el.Id] = leaf
+
+	if tree.root == nil {
+		tree.root = leaf
+		return
+	}
+
+	sibling := tree.findBestSibling(el.Rect)
+	oldParent := sibling.parent
+	newParent := &treeNode{
+		rect:   sibling.rect.Union(el.Rect),
+		height: sibling.height + 1,
+		parent: oldParent,
+		left:   sibling,
+		right:  leaf,
+	}
+	sibling.parent = newParent
+	leaf.parent = newParent
+	tree.replaceChild(oldParent, sibling, newParent)
+
+	tree.refit(newParent)
+}
+
+// Queries for elements that lie inside the given rect
+func (tree *AABBTree) Query(hitbox quadtree.Rect) []quadtree.QuadElement {
+	return tree.QueryAppend(hitbox, nil, nil)
+}
+
+// Appends the elements that lie inside the given rect and pass the filter to buf
+func (tree *AABBTree) QueryAppend(hitbox quadtree.Rect, filter quadtree.Filter, buf []quadtree.QuadElement) []quadtree.QuadElement {
+	tree.VisitQuery(hitbox, filter, func(el quadtree.QuadElement) bool {
+		buf = append(buf, el)
+		return true
+	})
+
+	return buf
+}
+
+// Calls the visitor with each element that lies inside the given rect and passes the filter,
+// until the visitor returns false
+func (tree *AABBTree) VisitQuery(hitbox quadtree.Rect, filter quadtree.Filter, visitor quadtree.Visitor) {
+	if tree.root != nil {
+		tree.visit(tree.root, hitbox, filter, visitor)
+	}
+}
+
+// Removes an element along with its parent, moving the element's sibling up in its place
+func (tree *AABBTree) Remove(el quadtree.QuadElement) {
+	leaf, ok := tree.leaves[el.Id]
+	if !ok {
+		return
+	}
+	delete(tree.leaves, el.Id)
+
+	if leaf == tree.root {
+		tree.root = nil
+		return
+	}
+
+	parent := leaf.parent
+	grandParent := parent.parent
+	sibling := parent.left
+	if sibling == leaf {
+		sibling = parent.right
+	}
+
+	sibling.parent = grandParent
+	tree.replaceChild(grandParent, parent, sibling)
+
+	tree.refit(grandParent)
+}
+
+// Moves an element from oldRect to newRect.
+// When the parent of the element's leaf still bounds newRect the tree keeps its shape and only the
+// rects of the ancestors are recomputed, otherwise the element is reinserted.
+func (tree *AABBTree) Move(id string, oldRect quadtree.Rect, newRect quadtree.Rect) {
+	leaf, ok := tree.leaves[id]
+	if !ok {
+		tree.Insert(quadtree.QuadElement{Rect: newRect, Id: id})
+		return
+	}
+
+	if leaf.parent == nil || leaf.parent.rect.Contains(newRect) {
+		leaf.rect = newRect
+		leaf.el.Rect = newRect
+
+		for node := leaf.parent; node != nil; node = node.parent {
+			node.rect = node.left.rect.Union(node.right.rect)
+		}
+		return
+	}
+
+	tree.Remove(leaf.el)
+	tree.Insert(quadtree.QuadElement{Rect: newRect, Id: id})
+}
+
+// returns false once the visitor has asked to stop
+func (tree *AABBTree) visit(node *treeNode, hitbox quadtree.Rect, filter quadtree.Filter, visitor quadtree.Visitor) bool {
+	if !hitbox.Intersects(node.rect) {
+		return true
+	}
+
+	if node.isLeaf() {
+		return (filter != nil && !filter(node.el)) || visitor(node.el)
+	}
+
+	return tree.visit(node.left, hitbox, filter, visitor) && tree.visit(node.right, hitbox, filter, visitor)
+}
+
+// Descends from the root towards the node that would grow the perimeter of the tree the least
+// when paired with the given rect. A branch stops the descent when pairing with it directly
+// is cheaper than pairing with either of its children.
+func (tree *AABBTree) findBestSibling(rect quadtree.Rect) *treeNode {
+	node := tree.root
+
+	for !node.isLeaf() {
+		combinedPerimeter := perimeter(node.rect.Union(rect))
+
+		cost := 2 * combinedPerimeter
+		// every ancestor of a deeper sibling has to grow to contain the rect as well
+		inheritedCost := 2 * (combinedPerimeter - perimeter(node.rect))
+
+		leftCost := descentCost(node.left, rect) + inheritedCost
+		rightCost := descentCost(node.right, rect) + inheritedCost
+
+		if cost < leftCost && cost < rightCost {
+			break
+		}
+
+		if leftCost < rightCost {
+			node = node.left
+		} else {
+			node = node.right
+		}
+	}
+
+	return node
+}
+
+// the cost of descending into the given child to look for a sibling for rect
+func descentCost(child *treeNode, rect quadtree.Rect) float32 {
+	if child.isLeaf() {
+		return perimeter(child.rect.Union(rect))
+	}
+
+	return perimeter(child.rect.Union(rect)) - perimeter(child.rect)
+}
+
+// Walks from the given node up to the root, rebalancing each node and recomputing its rect and height
+func (tree *AABBTree) refit(node *treeNode) {
+	for node != nil {
+		node = tree.balance(node)
+		node.height = 1 + max(node.left.height, node.right.height)
+		node.rect = node.left.rect.Union(node.right.rect)
+
+		node = node.parent
+	}
+}
+
+// Rotates the taller child of the given node above it when the heights of its children differ
+// by more than one. Returns the node that now sits where the given node was.
+func (tree *AABBTree) balance(node *treeNode) *treeNode {
+	if node.isLeaf() || node.height < 2 {
+		return node
+	}
+
+	heightDiff := node.right.height - node.left.height
+
+	if heightDiff > 1 {
+		return tree.rotateUp(node, node.right, node.left)
+	}
+
+	if heightDiff < -1 {
+		return tree.rotateUp(node, node.left, node.right)
+	}
+
+	return node
+}
+
+// Moves the tall child into the place of the node. The node keeps its short child and takes
+// the shorter grandchild of the tall child, while the tall child keeps the taller grandchild.
+func (tree *AABBTree) rotateUp(node *treeNode, tall *treeNode, short *treeNode) *treeNode {
+	tallGrandChild, shortGrandChild := tall.left, tall.right
+	if tallGrandChild.height < shortGrandChild.height {
+		tallGrandChild, shortGrandChild = shortGrandChild, tallGrandChild
+	}
+
+	tall.parent = node.parent
+	tree.replaceChild(node.parent, node, tall)
+
+	tall.left = node
+	tall.right = tallGrandChild
+	node.parent = tall
+
+	node.left = short
+	node.right = shortGrandChild
+	shortGrandChild.parent = node
+
+	node.rect = node.left.rect.Union(node.right.rect)
+	node.height = 1 + max(node.left.height, node.right.height)
+	tall.rect = tall.left.rect.Union(tall.right.rect)
+	tall.height = 1 + max(tall.left.height, tall.right.height)
+
+	return tall
+}
+
+// Points the parent at newChild instead of oldChild, or makes newChild the root when there is no parent
+func (tree *AABBTree) replaceChild(parent *treeNode, oldChild *treeNode, newChild *treeNode) {
+	if parent == nil {
+		tree.root = newChild
+	} else if parent.left == oldChild {
+		parent.left = newChild
+	} else {
+		parent.right = newChild
+	}
+}
+
+func perimeter(rect quadtree.Rect) float32 {
+	return 2 * (rect.W + rect.H)
+}
@@ -0,0 +1,192 @@
+package aabbtree
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/TheRaizer/GolangGame/util"
+	"github.com/TheRaizer/GolangGame/util/datastructures/quadtree"
+	"github.com/stretchr/testify/require"
+)
+
+// checks that every branch bounds its children, has a consistent height and is balanced
+func requireValidNode(t *testing.T, node *treeNode) {
+	if node.isLeaf() {
+		require.Equal(t, 0, node.height)
+		require.Equal(t, node.el.Rect, node.rect)
+		return
+	}
+
+	require.Same(t, node, node.left.parent)
+	require.Same(t, node, node.right.parent)
+	require.Equal(t, node.left.rect.Union(node.right.rect), node.rect)
+	require.Equal(t, 1+max(node.left.height, node.right.height), node.height)
+	require.LessOrEqual(t, node.left.height-node.right.height, 1)
+	require.GreaterOrEqual(t, node.left.height-node.right.height, -1)
+
+	requireValidNode(t, node.left)
+	requireValidNode(t, node.right)
+}
+
+func TestInsertShouldKeepTreeValid(t *testing.T) {
+	const NAME string = "should keep the tree valid after inserting %d elements"
+	getName := func(input int) string {
+		return fmt.Sprintf(NAME, input)
+	}
+
+	cases := []util.TestCase[int, struct{}]{
+		{Name: getName, Input: 1},
+		{Name: getName, Input: 2},
+		{Name: getName, Input: 17},
+		{Name: getName, Input: 200},
+	}
+
+	util.IterateTestCases(cases, t, func(testCase util.TestCase[int, struct{}]) {
+		tree := NewAABBTree()
+
+		// inserting in sorted order would degenerate an unbalanced tree into a list
+		for i := 0; i < testCase.Input; i++ {
+			tree.Insert(quadtree.QuadElement{
+				Rect: quadtree.Rect{X: float32(i) * 32, Y: 500, W: 32, H: 32},
+				Id:   fmt.Sprintf("id%d", i),
+			})
+		}
+
+		require.Len(t, tree.leaves, testCase.Input)
+		require.Nil(t, tree.root.parent)
+		requireValidNode(t, tree.root)
+	})
+}
+
+func TestQueryShouldMatchBruteForce(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	tree := NewAABBTree()
+	els := make([]quadtree.QuadElement, 0)
+
+	for i := 0; i < 100; i++ {
+		el := quadtree.QuadElement{
+			Rect: quadtree.Rect{
+				X: float32(random.Int31n(1000)) - 500,
+				Y: float32(random.Int31n(1000)) - 500,
+				W: float32(random.Int31n(60)) + 1,
+				H: float32(random.Int31n(60)) + 1,
+			},
+			Id: fmt.Sprintf("id%d", i),
+		}
+		els = append(els, el)
+		tree.Insert(el)
+	}
+
+	for i := 0; i < 50; i++ {
+		hitbox := quadtree.Rect{X: float32(random.Int31n(1000)) - 500, Y: float32(random.Int31n(1000)) - 500, W: 100, H: 100}
+
+		expected := make([]quadtree.QuadElement, 0)
+		for _, el := range els {
+			if hitbox.Intersects(el.Rect) {
+				expected = append(expected, el)
+			}
+		}
+
+		require.ElementsMatch(t, expected, tree.Query(hitbox))
+	}
+}
+
+func TestRemoveShouldKeepTreeValid(t *testing.T) {
+	tree := NewAABBTree()
+	els := make([]quadtree.QuadElement, 0)
+
+	for i := 0; i < 64; i++ {
+		el := quadtree.QuadElement{
+			Rect: quadtree.Rect{X: float32(i%8) * 40, Y: float32(i/8) * 40, W: 32, H: 32},
+			Id:   fmt.Sprintf("id%d", i),
+		}
+		els = append(els, el)
+		tree.Insert(el)
+	}
+
+	for i, el := range els {
+		tree.Remove(el)
+
+		remaining := els[i+1:]
+		require.Len(t, tree.leaves, len(remaining))
+		require.ElementsMatch(t, remaining, tree.Query(quadtree.Rect{X: 0, Y: 0, W: 400, H: 400}))
+
+		if len(remaining) > 0 {
+			requireValidNode(t, tree.root)
+		}
+	}
+
+	require.Nil(t, tree.root)
+	require.Empty(t, tree.Query(quadtree.Rect{X: 0, Y: 0, W: 400, H: 400}))
+}
+
+func TestMoveShouldMatchBruteForce(t *testing.T) {
+	random := rand.New(rand.NewSource(2))
+	tree := NewAABBTree()
+	els := make([]quadtree.QuadElement, 0)
+
+	for i := 0; i < 50; i++ {
+		el := quadtree.QuadElement{
+			Rect: quadtree.Rect{X: float32(random.Int31n(500)), Y: float32(random.Int31n(500)), W: 16, H: 16},
+			Id:   fmt.Sprintf("id%d", i),
+		}
+		els = append(els, el)
+		tree.Insert(el)
+	}
+
+	for step := 0; step < 300; step++ {
+		i := random.Intn(len(els))
+		newRect := quadtree.Rect{X: els[i].X + float32(random.Int31n(21)) - 10, Y: els[i].Y + float32(random.Int31n(21)) - 10, W: 16, H: 16}
+
+		tree.Move(els[i].Id, els[i].Rect, newRect)
+		els[i].Rect = newRect
+
+		requireValidNode(t, tree.root)
+		require.ElementsMatch(t, els, tree.Query(tree.root.rect))
+	}
+}
+
+func TestMoveInsideParentShouldKeepLeaf(t *testing.T) {
+	tree := NewAABBTree()
+	tree.Insert(quadtree.QuadElement{Rect: quadtree.Rect{X: 0, Y: 0, W: 10, H: 10}, Id: "id1"})
+	tree.Insert(quadtree.QuadElement{Rect: quadtree.Rect{X: 50, Y: 50, W: 10, H: 10}, Id: "id2"})
+	leaf := tree.leaves["id1"]
+
+	tree.Move("id1", quadtree.Rect{X: 0, Y: 0, W: 10, H: 10}, quadtree.Rect{X: 20, Y: 20, W: 10, H: 10})
+
+	require.Same(t, leaf, tree.leaves["id1"])
+	require.Equal(t, quadtree.Rect{X: 20, Y: 20, W: 40, H: 40}, tree.root.rect)
+}
+
+func TestVisitQueryShouldFilterAndStopEarly(t *testing.T) {
+	tree := NewAABBTree()
+	for i := 0; i < 10; i++ {
+		// wide enough to span several cells and overlap its neighbours
+		tree.Insert(quadtree.QuadElement{Rect: quadtree.Rect{X: float32(i) * 10, Y: 0, W: 25, H: 25}, Id: fmt.Sprintf("id%d", i)})
+	}
+	onlyEven := func(el quadtree.QuadElement) bool {
+		return int(el.X/10)%2 == 0
+	}
+
+	visited := make([]string, 0)
+	tree.VisitQuery(quadtree.Rect{X: 0, Y: 0, W: 200, H: 200}, onlyEven, func(el quadtree.QuadElement) bool {
+		visited = append(visited, el.Id)
+		return true
+	})
+	require.ElementsMatch(t, []string{"id0", "id2", "id4", "id6", "id8"}, visited)
+
+	visitedCount := 0
+	tree.VisitQuery(quadtree.Rect{X: 0, Y: 0, W: 200, H: 200}, nil, func(el quadtree.QuadElement) bool {
+		visitedCount++
+		return visitedCount < 4
+	})
+	require.Equal(t, 4, visitedCount)
+
+	buf := make([]quadtree.QuadElement, 0, 10)
+	allocs := testing.AllocsPerRun(10, func() {
+		buf = tree.QueryAppend(quadtree.Rect{X: 0, Y: 0, W: 200, H: 200}, onlyEven, buf[:0])
+	})
+	require.Zero(t, allocs)
+	require.Len(t, buf, 5)
+}
@@ -0,0 +1,62 @@
+package quadtree
+
+// Creates a quadtree holding all the given elements, building the nodes top down in a single pass
+// rather than inserting the elements one at a time and repeatedly splitting the same quads.
+// The resulting tree is the same shape as one that the elements were inserted in to, so it can
+// still be inserted in to, removed from and moved in afterwards.
+func NewQuadTreeFromElements(threshold uint8, maxDepth uint8, globalRect Rect, els []QuadElement) BaseQuadTree {
+	quadtree := NewQuadTree(threshold, maxDepth, globalRect)
+	if len(els) == 0 {
+		return quadtree
+	}
+
+	// grown towards each element in the order they would be inserted, as growing towards all of them at once
+	// can double the root in a different direction. The root is still a leaf so growing only stretches it.
+	for _, el := range els {
+		for !quadtree.globalRect.Contains(el.Rect) {
+			quadtree.grow(el.Rect)
+		}
+	}
+
+	// copy so that the tree never appends in to the callers slice
+	quadtree.build(quadtree.root, quadtree.globalRect, 0, append([]QuadElement(nil), els...))
+	return quadtree
+}
+
+// Distributes els amongst node and its descendants, splitting any node that holds more than the threshold
+func (quadtree *BaseQuadTree) build(node *QuadNode, nodeRect Rect, depth uint8, els []QuadElement) {
+	if depth >= quadtree.maxDepth+quadtree.growth || len(els) <= int(quadtree.threshold) {
+		node.els = els
+		return
+	}
+
+	var quadrantEls [4][]QuadElement
+	var nodeEls []QuadElement
+
+	for _, el := range els {
+		quadrantIdx := quadrantIdxContaining(nodeRect, el.Rect)
+		if quadrantIdx == -1 {
+			nodeEls = append(nodeEls, el)
+		} else {
+			quadrantEls[quadrantIdx] = append(quadrantEls[quadrantIdx], el)
+		}
+	}
+
+	node.els = nodeEls
+	for i := range node.children {
+		node.children[i] = &QuadNode{}
+		quadtree.build(node.children[i], computeQuadRect(nodeRect, i), depth+1, quadrantEls[i])
+	}
+}
+
+// same as QuadrantContaining but only returns the index, so that it does not allocate the quadrant rect
+func quadrantIdxContaining(nodeRect Rect, rect Rect) int {
+	for i := 0; i < 4; i++ {
+		quadRect := computeQuadRect(nodeRect, i)
+		if quadRect.Contains(rect) {
+			return i
+		}
+	}
+
+	return -1
+}
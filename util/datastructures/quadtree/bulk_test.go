@@ -0,0 +1,132 @@
+package quadtree
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/TheRaizer/GolangGame/util"
+	"github.com/stretchr/testify/require"
+)
+
+func randomEls(random *rand.Rand, n int, span int32) []QuadElement {
+	els := make([]QuadElement, 0, n)
+	for i := 0; i < n; i++ {
+		rect := Rect{float32(random.Int31n(span)), float32(random.Int31n(span)), float32(random.Int31n(30) + 1), float32(random.Int31n(30) + 1)}
+		els = append(els, QuadElement{rect, fmt.Sprintf("id%d", i)})
+	}
+
+	return els
+}
+
+func TestNewQuadTreeFromElementsShouldMatchInsertion(t *testing.T) {
+	type TestInput struct {
+		globalRect Rect
+		els        []QuadElement
+	}
+
+	const NAME string = "should build the same tree as inserting %d elements in to %+v"
+	getName := func(input TestInput) string {
+		return fmt.Sprintf(NAME, len(input.els), input.globalRect)
+	}
+
+	withEls := func(globalRect Rect, n int) TestInput {
+		random := rand.New(rand.NewSource(int64(n)))
+		return TestInput{globalRect, randomEls(random, n, 370)}
+	}
+
+	// the root grows left past the second element before the first, rather than towards both at once
+	growing := []QuadElement{{Rect{250, 20, 10, 10}, "right"}, {Rect{-150, 20, 10, 10}, "left"}}
+	for i := 0; i < 6; i++ {
+		growing = append(growing, QuadElement{Rect{float32(i * 15), float32(i * 15), 5, 5}, fmt.Sprintf("id%d", i)})
+	}
+
+	var cases = []util.TestCase[TestInput, any]{
+		{Name: getName, Input: withEls(Rect{0, 0, 400, 400}, 0)},
+		{Name: getName, Input: withEls(Rect{0, 0, 400, 400}, 3)},
+		{Name: getName, Input: withEls(Rect{0, 0, 400, 400}, 50)},
+		{Name: getName, Input: withEls(Rect{0, 0, 400, 400}, 500)},
+		// the root has to grow to fit the elements
+		{Name: getName, Input: withEls(Rect{0, 0, 100, 100}, 500)},
+		{Name: getName, Input: TestInput{Rect{0, 0, 100, 100}, growing}},
+	}
+
+	util.IterateTestCases(cases, t, func(testCase util.TestCase[TestInput, any]) {
+		inserted := NewQuadTree(3, 5, testCase.Input.globalRect)
+		for _, el := range testCase.Input.els {
+			inserted.Insert(el)
+		}
+
+		bulk := NewQuadTreeFromElements(3, 5, testCase.Input.globalRect, testCase.Input.els)
+
+		require.NoError(t, bulk.Validate())
+		require.Equal(t, inserted.globalRect, bulk.globalRect)
+		require.Equal(t, inserted.Stats(), bulk.Stats())
+
+		insertedJSON, err := inserted.ExportJSON()
+		require.NoError(t, err)
+		bulkJSON, err := bulk.ExportJSON()
+		require.NoError(t, err)
+		require.JSONEq(t, string(insertedJSON), string(bulkJSON))
+	})
+}
+
+func TestNewQuadTreeFromElementsShouldGrowToFitElements(t *testing.T) {
+	random := rand.New(rand.NewSource(4))
+	els := randomEls(random, 200, 1000)
+	els = append(els, QuadElement{Rect{-300, -20, 10, 10}, "outside"})
+
+	tree := NewQuadTreeFromElements(4, 4, Rect{0, 0, 100, 100}, els)
+
+	require.NoError(t, tree.Validate())
+	require.ElementsMatch(t, els, tree.Query(tree.globalRect))
+
+	for i := 0; i < 50; i++ {
+		hitbox := Rect{float32(random.Int31n(1400)) - 350, float32(random.Int31n(1400)) - 350, 120, 120}
+		expected := make([]QuadElement, 0)
+		for _, el := range els {
+			if hitbox.Intersects(el.Rect) {
+				expected = append(expected, el)
+			}
+		}
+
+		require.ElementsMatch(t, expected, tree.Query(hitbox))
+	}
+
+	// a bulk built tree must support the usual updates
+	for _, el := range els {
+		tree.Remove(el)
+	}
+	require.NoError(t, tree.Validate())
+	require.Empty(t, tree.Query(tree.globalRect))
+	require.Equal(t, Rect{0, 0, 100, 100}, tree.globalRect)
+}
+
+func TestNewQuadTreeFromElementsShouldNotAliasElements(t *testing.T) {
+	els := []QuadElement{{Rect{0, 0, 5, 5}, "id1"}, {Rect{50, 50, 5, 5}, "id2"}}
+
+	tree := NewQuadTreeFromElements(4, 4, Rect{0, 0, 100, 100}, els[:1])
+	tree.Insert(QuadElement{Rect{10, 10, 5, 5}, "id3"})
+
+	require.Equal(t, QuadElement{Rect{50, 50, 5, 5}, "id2"}, els[1])
+}
+
+func BenchmarkBuildQuadTree(b *testing.B) {
+	random := rand.New(rand.NewSource(1))
+	els := randomEls(random, 5000, 4000)
+
+	b.Run("insert", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			tree := NewQuadTree(7, 5, Rect{0, 0, 4096, 4096})
+			for _, el := range els {
+				tree.Insert(el)
+			}
+		}
+	})
+
+	b.Run("bulk", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			NewQuadTreeFromElements(7, 5, Rect{0, 0, 4096, 4096}, els)
+		}
+	})
+}
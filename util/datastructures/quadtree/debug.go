@@ -0,0 +1,183 @@
+package quadtree
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Summary of the shape of a quadtree, useful when tuning its threshold and max depth
+type Stats struct {
+	Nodes          int
+	Leaves         int
+	DepthHistogram []int // number of nodes at each depth, starting from the root
+	LeafEls        int   // number of elements stored in leaf nodes
+	InteriorEls    int   // number of elements stored in interior nodes since they fit no quadrant
+	MaxLeafEls     int   // most elements stored in a single leaf
+}
+
+// The average number of elements stored per leaf
+func (stats Stats) AvgLeafEls() float64 {
+	if stats.Leaves == 0 {
+		return 0
+	}
+
+	return float64(stats.LeafEls) / float64(stats.Leaves)
+}
+
+// Walks the tree and collects statistics on its nodes and elements
+func (quadtree *BaseQuadTree) Stats() Stats {
+	stats := Stats{}
+
+	quadtree.walk(func(node *QuadNode, _ Rect, depth int) {
+		stats.Nodes++
+
+		for len(stats.DepthHistogram) <= depth {
+			stats.DepthHistogram = append(stats.DepthHistogram, 0)
+		}
+		stats.DepthHistogram[depth]++
+
+		if node.isLeaf() {
+			stats.Leaves++
+			stats.LeafEls += len(node.els)
+			stats.MaxLeafEls = max(stats.MaxLeafEls, len(node.els))
+		} else {
+			stats.InteriorEls += len(node.els)
+		}
+	})
+
+	return stats
+}
+
+// Checks the structural invariants of the tree and returns every violation that was found:
+// each element is contained by its node's quad and stored as deep as it can be,
+// ids are unique, nodes do not pass the max depth and no node is left that could have been merged.
+func (quadtree *BaseQuadTree) Validate() error {
+	if quadtree.root == nil {
+		return errors.New("root node is nil")
+	}
+
+	var errs []error
+	seen := make(map[string]bool)
+
+	quadtree.walk(func(node *QuadNode, nodeRect Rect, depth int) {
+		if depth > int(quadtree.maxDepth+quadtree.growth) {
+			errs = append(errs, fmt.Errorf("node at %+v has depth %d past the max depth", nodeRect, depth))
+		}
+
+		for _, el := range node.els {
+			if seen[el.Id] {
+				errs = append(errs, fmt.Errorf("duplicate element id: %s", el.Id))
+			}
+			seen[el.Id] = true
+
+			if !nodeRect.Contains(el.Rect) {
+				errs = append(errs, fmt.Errorf("element %s at %+v is not contained by its node at %+v", el.Id, el.Rect, nodeRect))
+			} else if !node.isLeaf() && !fitsNoQuadrant(nodeRect, el.Rect) {
+				errs = append(errs, fmt.Errorf("element %s at %+v should be stored in a quadrant of %+v", el.Id, el.Rect, nodeRect))
+			}
+		}
+
+		if !node.isLeaf() && quadtree.isMergeable(node) {
+			errs = append(errs, fmt.Errorf("node at %+v should have been merged with its children", nodeRect))
+		}
+	})
+
+	return errors.Join(errs...)
+}
+
+// A node of the tree in a form that can be marshalled
+type NodeExport struct {
+	Rect     Rect          `json:"rect"`
+	Depth    int           `json:"depth"`
+	Els      []QuadElement `json:"els"`
+	Children []NodeExport  `json:"children,omitempty"`
+}
+
+// Exports the tree as nested JSON nodes
+func (quadtree *BaseQuadTree) ExportJSON() ([]byte, error) {
+	if quadtree.root == nil {
+		return nil, errors.New("root node is nil")
+	}
+
+	return json.MarshalIndent(exportNode(quadtree.root, quadtree.globalRect, 0), "", "  ")
+}
+
+func exportNode(node *QuadNode, nodeRect Rect, depth int) NodeExport {
+	export := NodeExport{
+		Rect:  nodeRect,
+		Depth: depth,
+		Els:   append([]QuadElement{}, node.els...),
+	}
+
+	if !node.isLeaf() {
+		for i, child := range node.children {
+			export.Children = append(export.Children, exportNode(child, *ComputeQuadRect(nodeRect, i), depth+1))
+		}
+	}
+
+	return export
+}
+
+var quadrantNames = [4]string{"NW", "NE", "SW", "SE"}
+
+// Exports the tree as a Graphviz DOT digraph, where each node is labelled with its quad and element ids.
+// Render with: dot -Tsvg tree.dot -o tree.svg
+func (quadtree *BaseQuadTree) ExportDOT() string {
+	var builder strings.Builder
+	builder.WriteString("digraph quadtree {\n\tnode [shape=box];\n")
+
+	if quadtree.root != nil {
+		nextId := 0
+		exportDOTNode(&builder, quadtree.root, quadtree.globalRect, &nextId)
+	}
+
+	builder.WriteString("}\n")
+	return builder.String()
+}
+
+// writes the node and its descendants, returning the name given to the node
+func exportDOTNode(builder *strings.Builder, node *QuadNode, nodeRect Rect, nextId *int) string {
+	name := fmt.Sprintf("n%d", *nextId)
+	*nextId++
+
+	ids := make([]string, 0, len(node.els))
+	for _, el := range node.els {
+		ids = append(ids, strings.ReplaceAll(el.Id, `"`, `\"`))
+	}
+	label := fmt.Sprintf("x=%g y=%g w=%g h=%g", nodeRect.X, nodeRect.Y, nodeRect.W, nodeRect.H)
+	if len(ids) > 0 {
+		// a DOT escaped newline separates the quad from the ids
+		label += `\n` + strings.Join(ids, ", ")
+	}
+	fmt.Fprintf(builder, "\t%s [label=\"%s\"];\n", name, label)
+
+	if !node.isLeaf() {
+		for i, child := range node.children {
+			childName := exportDOTNode(builder, child, *ComputeQuadRect(nodeRect, i), nextId)
+			fmt.Fprintf(builder, "\t%s -> %s [label=%q];\n", name, childName, quadrantNames[i])
+		}
+	}
+
+	return name
+}
+
+// calls fn on every node of the tree in depth first order along with the node's quad and depth
+func (quadtree *BaseQuadTree) walk(fn func(node *QuadNode, nodeRect Rect, depth int)) {
+	if quadtree.root == nil {
+		panic("node pointer was nil")
+	}
+
+	walkNode(quadtree.root, quadtree.globalRect, 0, fn)
+}
+
+func walkNode(node *QuadNode, nodeRect Rect, depth int, fn func(node *QuadNode, nodeRect Rect, depth int)) {
+	fn(node, nodeRect, depth)
+
+	if !node.isLeaf() {
+		for i, child := range node.children {
+			walkNode(child, *ComputeQuadRect(nodeRect, i), depth+1, fn)
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package quadtree
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/TheRaizer/GolangGame/util"
+	"github.com/stretchr/testify/require"
+)
+
+// a tree with elements in a leaf at depth 2, in interior nodes and in leaves at depth 1
+func newDebugTree() BaseQuadTree {
+	return BaseQuadTree{
+		threshold:  2,
+		maxDepth:   4,
+		globalRect: Rect{0, 0, 100, 100},
+		root: &QuadNode{
+			children: [4]*QuadNode{
+				{
+					children: [4]*QuadNode{
+						{els: []QuadElement{{Rect{0, 0, 5, 5}, "id1"}, {Rect{1, 1, 5, 5}, "id2"}, {Rect{2, 2, 5, 5}, "id3"}}},
+						{},
+						{},
+						{},
+					},
+					els: []QuadElement{{Rect{20, 20, 10, 10}, "id4"}},
+				},
+				{els: []QuadElement{{Rect{60, 10, 5, 5}, "id5"}}},
+				{},
+				{},
+			},
+			els: []QuadElement{{Rect{45, 45, 10, 10}, "id6"}},
+		},
+	}
+}
+
+func TestStats(t *testing.T) {
+	tree := newDebugTree()
+
+	stats := tree.Stats()
+
+	require.Equal(t, Stats{
+		Nodes:          9,
+		Leaves:         7,
+		DepthHistogram: []int{1, 4, 4},
+		LeafEls:        4,
+		InteriorEls:    2,
+		MaxLeafEls:     3,
+	}, stats)
+	require.InDelta(t, 4.0/7.0, stats.AvgLeafEls(), 0.0001)
+}
+
+func TestValidateShouldAcceptValidTrees(t *testing.T) {
+	debugTree := newDebugTree()
+	require.NoError(t, debugTree.Validate())
+
+	random := rand.New(rand.NewSource(3))
+	tree := NewQuadTree(3, 5, Rect{0, 0, 400, 400})
+	els := make([]QuadElement, 0)
+
+	for i := 0; i < 80; i++ {
+		el := QuadElement{Rect{float32(random.Int31n(500)) - 50, float32(random.Int31n(500)) - 50, 10, 10}, fmt.Sprintf("id%d", i)}
+		els = append(els, el)
+		tree.Insert(el)
+		require.NoError(t, tree.Validate())
+	}
+
+	for step := 0; step < 300; step++ {
+		i := random.Intn(len(els))
+		newRect := Rect{els[i].X + float32(random.Int31n(61)) - 30, els[i].Y + float32(random.Int31n(61)) - 30, 10, 10}
+
+		tree.Move(els[i].Id, els[i].Rect, newRect)
+		els[i].Rect = newRect
+		require.NoError(t, tree.Validate())
+	}
+
+	for _, el := range els {
+		tree.Remove(el)
+		require.NoError(t, tree.Validate())
+	}
+}
+
+func TestValidateShouldReportViolations(t *testing.T) {
+	const NAME string = "should report that %s"
+	getName := func(input string) string {
+		return fmt.Sprintf(NAME, input)
+	}
+
+	trees := map[string]BaseQuadTree{
+		"an element is outside its node": {
+			threshold:  2,
+			maxDepth:   4,
+			globalRect: Rect{0, 0, 100, 100},
+			root:       &QuadNode{els: []QuadElement{{Rect{95, 95, 10, 10}, "id1"}}},
+		},
+		"an id is duplicated": {
+			threshold:  2,
+			maxDepth:   4,
+			globalRect: Rect{0, 0, 100, 100},
+			root:       &QuadNode{els: []QuadElement{{Rect{0, 0, 5, 5}, "id1"}, {Rect{5, 5, 5, 5}, "id1"}}},
+		},
+		"a node should be merged": {
+			threshold:  2,
+			maxDepth:   4,
+			globalRect: Rect{0, 0, 100, 100},
+			root: &QuadNode{
+				children: [4]*QuadNode{{els: []QuadElement{{Rect{0, 0, 5, 5}, "id1"}}}, {}, {}, {}},
+			},
+		},
+		"an element should be in a quadrant": {
+			threshold:  1,
+			maxDepth:   4,
+			globalRect: Rect{0, 0, 100, 100},
+			root: &QuadNode{
+				children: [4]*QuadNode{{els: []QuadElement{{Rect{0, 0, 5, 5}, "id1"}}}, {}, {}, {}},
+				els:      []QuadElement{{Rect{60, 60, 5, 5}, "id2"}},
+			},
+		},
+		"a node is too deep": {
+			threshold:  0,
+			maxDepth:   0,
+			globalRect: Rect{0, 0, 100, 100},
+			root: &QuadNode{
+				children: [4]*QuadNode{{els: []QuadElement{{Rect{0, 0, 5, 5}, "id1"}}}, {}, {}, {}},
+			},
+		},
+	}
+
+	cases := make([]util.TestCase[string, string], 0)
+	expectedErrs := map[string]string{
+		"an element is outside its node":     "element id1 at {X:95 Y:95 W:10 H:10} is not contained by its node at {X:0 Y:0 W:100 H:100}",
+		"an id is duplicated":                "duplicate element id: id1",
+		"a node should be merged":            "node at {X:0 Y:0 W:100 H:100} should have been merged with its children",
+		"an element should be in a quadrant": "element id2 at {X:60 Y:60 W:5 H:5} should be stored in a quadrant of {X:0 Y:0 W:100 H:100}",
+		"a node is too deep":                 "node at {X:0 Y:0 W:50 H:50} has depth 1 past the max depth",
+	}
+	for violation, expectedErr := range expectedErrs {
+		cases = append(cases, util.TestCase[string, string]{Name: getName, Input: violation, Expected: expectedErr})
+	}
+
+	util.IterateTestCases(cases, t, func(testCase util.TestCase[string, string]) {
+		tree := trees[testCase.Input]
+		err := tree.Validate()
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), testCase.Expected)
+	})
+}
+
+func TestExportJSON(t *testing.T) {
+	tree := newDebugTree()
+
+	data, err := tree.ExportJSON()
+	require.NoError(t, err)
+
+	var export NodeExport
+	require.NoError(t, json.Unmarshal(data, &export))
+
+	require.Equal(t, Rect{0, 0, 100, 100}, export.Rect)
+	require.Equal(t, []QuadElement{{Rect{45, 45, 10, 10}, "id6"}}, export.Els)
+	require.Len(t, export.Children, 4)
+	require.Equal(t, 2, export.Children[0].Children[0].Depth)
+	require.Equal(t, Rect{0, 0, 25, 25}, export.Children[0].Children[0].Rect)
+	require.Len(t, export.Children[0].Children[0].Els, 3)
+	require.Empty(t, export.Children[2].Children)
+}
+
+func TestExportDOT(t *testing.T) {
+	tree := BaseQuadTree{
+		threshold:  1,
+		maxDepth:   4,
+		globalRect: Rect{0, 0, 100, 100},
+		root: &QuadNode{
+			children: [4]*QuadNode{{els: []QuadElement{{Rect{0, 0, 5, 5}, "id1"}}}, {}, {}, {}},
+			els:      []QuadElement{{Rect{45, 45, 10, 10}, "id2"}, {Rect{40, 45, 10, 10}, "id3"}},
+		},
+	}
+
+	expected := `digraph quadtree {
+	node [shape=box];
+	n0 [label="x=0 y=0 w=100 h=100\nid2, id3"];
+	n1 [label="x=0 y=0 w=50 h=50\nid1"];
+	n0 -> n1 [label="NW"];
+	n2 [label="x=50 y=0 w=50 h=50"];
+	n0 -> n2 [label="NE"];
+	n3 [label="x=0 y=50 w=50 h=50"];
+	n0 -> n3 [label="SW"];
+	n4 [label="x=50 y=50 w=50 h=50"];
+	n0 -> n4 [label="SE"];
+}
+`
+
+	require.Equal(t, expected, tree.ExportDOT())
+}
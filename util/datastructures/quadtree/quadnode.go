@@ -46,22 +46,30 @@ func (quadNode *QuadNode) split(quadRect Rect) {
 // 3 = SE
 // otherwise nil
 func ComputeQuadRect(parentRect Rect, quadrantIdx int) *Rect {
+	if quadrantIdx < 0 || quadrantIdx > 3 {
+		return nil
+	}
+
+	rect := computeQuadRect(parentRect, quadrantIdx)
+	return &rect
+}
+
+// same as ComputeQuadRect for a valid quadrantIdx, but returns the rect by value so that hot paths
+// such as queries do not allocate
+func computeQuadRect(parentRect Rect, quadrantIdx int) Rect {
 	width := parentRect.W / 2
 	height := parentRect.H / 2
+	rect := Rect{X: parentRect.X, Y: parentRect.Y, W: width, H: height}
 
-	switch quadrantIdx {
-	case 0:
-		return &Rect{X: parentRect.X, Y: parentRect.Y, W: width, H: height}
-	case 1:
-		return &Rect{X: parentRect.X + width, Y: parentRect.Y, W: width, H: height}
-	case 2:
-		return &Rect{X: parentRect.X, Y: parentRect.Y + height, W: width, H: height}
-	case 3:
-		return &Rect{X: parentRect.X + width, Y: parentRect.Y + height, W: width, H: height}
-	default:
-		return nil
+	if quadrantIdx == 1 || quadrantIdx == 3 {
+		rect.X += width
+	}
 
+	if quadrantIdx == 2 || quadrantIdx == 3 {
+		rect.Y += height
 	}
+
+	return rect
 }
 
 // Returns the index of the quadrant in quadRect that contains all of el as well as the quadrants corresponding rect.
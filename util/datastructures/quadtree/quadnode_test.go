@@ -129,28 +129,28 @@ func TestComputeQuadRect(t *testing.T) {
 				return fmt.Sprintf("Should return correct %d index (NW) rect", input)
 			},
 			Input:    0,
-			Expected: []*Rect{{0, 0, 5, 5}, {1, 30, 44, 6}, {23, 11, 15, 11}},
+			Expected: []*Rect{{0, 0, 5, 5}, {1, 30, 44, 6.5}, {23, 11, 15, 11}},
 		},
 		{
 			Name: func(input int) string {
 				return fmt.Sprintf("Should return correct %d index (NE) rect", input)
 			},
 			Input:    1,
-			Expected: []*Rect{{5, 0, 5, 5}, {45, 30, 44, 6}, {38, 11, 15, 11}},
+			Expected: []*Rect{{5, 0, 5, 5}, {45, 30, 44, 6.5}, {38, 11, 15, 11}},
 		},
 		{
 			Name: func(input int) string {
 				return fmt.Sprintf("Should return correct %d index (SW) rect", input)
 			},
 			Input:    2,
-			Expected: []*Rect{{0, 5, 5, 5}, {1, 36, 44, 6}, {23, 22, 15, 11}},
+			Expected: []*Rect{{0, 5, 5, 5}, {1, 36.5, 44, 6.5}, {23, 22, 15, 11}},
 		},
 		{
 			Name: func(input int) string {
 				return fmt.Sprintf("Should return correct %d index (SE) rect", input)
 			},
 			Input:    3,
-			Expected: []*Rect{{5, 5, 5, 5}, {45, 36, 44, 6}, {38, 22, 15, 11}},
+			Expected: []*Rect{{5, 5, 5, 5}, {45, 36.5, 44, 6.5}, {38, 22, 15, 11}},
 		},
 	}
 
@@ -7,14 +7,26 @@ import "github.com/TheRaizer/GolangGame/util"
 type QuadTree interface {
 	Insert(el QuadElement)
 	Query(hitbox Rect) []QuadElement
+	QueryAppend(hitbox Rect, filter Filter, buf []QuadElement) []QuadElement
+	VisitQuery(hitbox Rect, filter Filter, visitor Visitor)
 	Remove(el QuadElement)
+	Move(id string, oldRect Rect, newRect Rect)
 }
 
+// Decides whether a queried element should be included in the results.
+// A nil filter includes every element.
+type Filter func(el QuadElement) bool
+
+// Called with each queried element, returning false stops the query early
+type Visitor func(el QuadElement) bool
+
 type BaseQuadTree struct {
 	threshold uint8 // max number of elements before we split the quad
 	maxDepth  uint8 // max number of times we will allow quads to be split
 
-	globalRect Rect
+	globalRect Rect  // the rect covered by the root, grows and shrinks with the elements
+	baseRect   Rect  // the rect the tree was created with, the root never shrinks past it
+	growth     uint8 // number of times the root has been doubled past baseRect
 	root       *QuadNode
 }
 
@@ -23,58 +35,268 @@ func NewQuadTree(threshold uint8, maxDepth uint8, globalRect Rect) BaseQuadTree
 		threshold,
 		maxDepth,
 		globalRect,
+		globalRect,
+		0,
 		&QuadNode{},
 	}
 }
 
-// Inserts an element into the quadtree
+// Inserts an element into the quadtree.
+// If the element lies outside of the root quad, the root is grown towards it until it fits.
 func (quadtree *BaseQuadTree) Insert(el QuadElement) {
+	for !quadtree.globalRect.Contains(el.Rect) {
+		quadtree.grow(el.Rect)
+	}
+
 	quadtree.insert(quadtree.root, quadtree.globalRect, 0, el)
 }
 
 // Queries for elements that lie inside the given rect
 func (quadtree *BaseQuadTree) Query(hitbox Rect) []QuadElement {
-	return quadtree.query(quadtree.root, quadtree.globalRect, hitbox)
+	return quadtree.QueryAppend(hitbox, nil, nil)
+}
+
+// Appends the elements that lie inside the given rect and pass the filter to buf.
+// Reusing buf between queries avoids allocating a new slice each time.
+func (quadtree *BaseQuadTree) QueryAppend(hitbox Rect, filter Filter, buf []QuadElement) []QuadElement {
+	quadtree.VisitQuery(hitbox, filter, func(el QuadElement) bool {
+		buf = append(buf, el)
+		return true
+	})
+
+	return buf
 }
 
-// Removes an element from the quad tree
+// Calls the visitor with each element that lies inside the given rect and passes the filter,
+// until the visitor returns false
+func (quadtree *BaseQuadTree) VisitQuery(hitbox Rect, filter Filter, visitor Visitor) {
+	quadtree.visit(quadtree.root, quadtree.globalRect, hitbox, filter, visitor)
+}
+
+// Removes an element from the quad tree.
+// Elements outside of the root quad can never have been inserted, so removing them does nothing.
 func (quadtree *BaseQuadTree) Remove(el QuadElement) {
+	if !quadtree.globalRect.Contains(el.Rect) {
+		return
+	}
+
 	quadtree.remove(quadtree.root, quadtree.globalRect, el)
+	quadtree.shrink()
+}
+
+// Moves the element with the given id from oldRect to newRect.
+// The element is updated in place when the node storing it would still store it at newRect.
+// Otherwise it is reinserted starting from the closest ancestor whose quad contains newRect,
+// rather than from the root.
+func (quadtree *BaseQuadTree) Move(id string, oldRect Rect, newRect Rect) {
+	if !quadtree.globalRect.Contains(oldRect) {
+		quadtree.Insert(QuadElement{Rect: newRect, Id: id})
+		return
+	}
+
+	path, pathRects := quadtree.pathTo(oldRect)
+	depth := len(path) - 1
+	node, nodeRect := path[depth], pathRects[depth]
+
+	if nodeRect.Contains(newRect) && (node.isLeaf() || fitsNoQuadrant(nodeRect, newRect)) {
+		replaceValue(node, QuadElement{Rect: newRect, Id: id})
+		return
+	}
+
+	removeValue(node, QuadElement{Rect: oldRect, Id: id})
+
+	// walk up only as far as the first quad that contains the new rect
+	ancestorDepth := depth
+	for ancestorDepth > 0 && !pathRects[ancestorDepth].Contains(newRect) {
+		ancestorDepth--
+	}
+
+	// merge the quads the element left, but never past the ancestor we are about to insert into
+	mergeFrom := depth - 1
+	if !node.isLeaf() {
+		mergeFrom = depth
+	}
+
+	for i := mergeFrom; i >= ancestorDepth; i-- {
+		if !quadtree.tryMerge(path[i]) {
+			break
+		}
+	}
+
+	el := QuadElement{Rect: newRect, Id: id}
+	if pathRects[ancestorDepth].Contains(newRect) {
+		quadtree.insert(path[ancestorDepth], pathRects[ancestorDepth], uint8(ancestorDepth), el)
+	} else {
+		// the element left the root quad entirely so the root needs to grow
+		quadtree.Insert(el)
+	}
+
+	// the ancestor may have merged, which could allow the quads above it to merge as well
+	for i := ancestorDepth - 1; i >= 0; i-- {
+		if !quadtree.tryMerge(path[i]) {
+			break
+		}
+	}
+
+	quadtree.shrink()
+}
+
+// Returns the nodes and their quads from the root down to the node that stores an element with the given rect
+func (quadtree *BaseQuadTree) pathTo(rect Rect) ([]*QuadNode, []Rect) {
+	if quadtree.root == nil {
+		panic("node pointer was nil")
+	}
+
+	node, nodeRect := quadtree.root, quadtree.globalRect
+	path, pathRects := []*QuadNode{node}, []Rect{nodeRect}
+
+	for !node.isLeaf() {
+		quadrantIdx, quadRect := QuadrantContaining(nodeRect, QuadElement{Rect: rect})
+		if quadrantIdx == -1 {
+			break
+		}
+
+		node, nodeRect = node.children[quadrantIdx], *quadRect
+		path, pathRects = append(path, node), append(pathRects, nodeRect)
+	}
+
+	return path, pathRects
+}
+
+// Doubles the root quad in the direction of the given rect.
+// The old root becomes the quadrant of the new root that covers the old root quad.
+// A leaf root is simply stretched since all its elements are still contained by the larger quad.
+func (quadtree *BaseQuadTree) grow(towards Rect) {
+	if quadtree.root == nil {
+		panic("node pointer was nil")
+	}
+
+	oldRect := quadtree.globalRect
+	newRect := Rect{X: oldRect.X, Y: oldRect.Y, W: max(oldRect.W*2, 1), H: max(oldRect.H*2, 1)}
+	oldQuadrant := 0
+
+	if towards.X < oldRect.X {
+		newRect.X -= oldRect.W
+		oldQuadrant += 1
+	}
+
+	if towards.Y < oldRect.Y {
+		newRect.Y -= oldRect.H
+		oldQuadrant += 2
+	}
+
+	if !quadtree.root.isLeaf() {
+		newRoot := &QuadNode{}
+		for i := range newRoot.children {
+			if i == oldQuadrant {
+				newRoot.children[i] = quadtree.root
+			} else {
+				newRoot.children[i] = &QuadNode{}
+			}
+		}
+		quadtree.root = newRoot
+	}
+
+	quadtree.globalRect = newRect
+	quadtree.growth++
+}
+
+// Undoes the growth of the root for as long as a single quadrant of the root
+// contains the base rect along with every element in the tree.
+func (quadtree *BaseQuadTree) shrink() {
+	for quadtree.growth > 0 {
+		quadrantIdx, quadRect := quadtree.shrinkableQuadrant()
+		if quadrantIdx == -1 {
+			return
+		}
+
+		if !quadtree.root.isLeaf() {
+			quadtree.root = quadtree.root.children[quadrantIdx]
+		}
+
+		quadtree.globalRect = *quadRect
+		quadtree.growth--
+	}
 }
 
-func (quadtree *BaseQuadTree) query(node *QuadNode, nodeRect Rect, hitbox Rect) []QuadElement {
-	var intersectingEls []QuadElement
+// Returns the index and rect of the root quadrant that the root can be shrunk in to.
+// Returns -1 and a nil pointer when the root cannot be shrunk.
+func (quadtree *BaseQuadTree) shrinkableQuadrant() (quadrantIdx int, quadRect *Rect) {
+	root := quadtree.root
+
+	for i := 0; i < 4; i++ {
+		rect := ComputeQuadRect(quadtree.globalRect, i)
+		if !rect.Contains(quadtree.baseRect) {
+			continue
+		}
 
+		if root.isLeaf() {
+			if allContained(*rect, root.els) {
+				return i, rect
+			}
+			continue
+		}
+
+		// elements stored in an interior node do not fit in any of its quadrants
+		if len(root.els) > 0 {
+			return -1, nil
+		}
+
+		othersEmpty := true
+		for j, child := range root.children {
+			if j != i && (!child.isLeaf() || len(child.els) > 0) {
+				othersEmpty = false
+				break
+			}
+		}
+
+		if othersEmpty {
+			return i, rect
+		}
+	}
+
+	return -1, nil
+}
+
+func allContained(rect Rect, els []QuadElement) bool {
+	for _, el := range els {
+		if !rect.Contains(el.Rect) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// returns false once the visitor has asked to stop
+func (quadtree *BaseQuadTree) visit(node *QuadNode, nodeRect Rect, hitbox Rect, filter Filter, visitor Visitor) bool {
 	if node == nil {
 		panic("node pointer was nil")
 	}
 
 	if !nodeRect.Intersects(hitbox) {
-		return intersectingEls
+		return true
 	}
 
-	if node.isLeaf() {
-		for _, el := range node.els {
-			if hitbox.Intersects(el.Rect) {
-				intersectingEls = append(intersectingEls, el)
-			}
+	// interior nodes also store the elements that do not fit in any of their quadrants
+	for _, el := range node.els {
+		if hitbox.Intersects(el.Rect) && (filter == nil || filter(el)) && !visitor(el) {
+			return false
 		}
-	} else {
-		// call query on only the quadrants that intersect with the hitbox
+	}
+
+	if !node.isLeaf() {
+		// visit only the quadrants that intersect with the hitbox
 		// children cannot be nil since the node is not a leaf
 		for i, quadNode := range node.children {
-			quadRect := ComputeQuadRect(nodeRect, i)
-			if hitbox.Intersects(*quadRect) {
-				intersectingQuadEls := quadtree.query(quadNode, *quadRect, hitbox)
-				intersectingEls = append(intersectingEls, intersectingQuadEls...)
+			quadRect := computeQuadRect(nodeRect, i)
+			if hitbox.Intersects(quadRect) && !quadtree.visit(quadNode, quadRect, hitbox, filter, visitor) {
+				return false
 			}
-
 		}
-
-		return intersectingEls
 	}
 
-	return intersectingEls
+	return true
 }
 
 func (quadtree *BaseQuadTree) remove(node *QuadNode, nodeRect Rect, el QuadElement) bool {
@@ -95,7 +317,8 @@ func (quadtree *BaseQuadTree) remove(node *QuadNode, nodeRect Rect, el QuadEleme
 
 		if quadrantIdx == -1 {
 			removeValue(node, el)
-			return false
+			// the interior node may now hold few enough elements to absorb its children
+			return quadtree.tryMerge(node)
 		} else {
 			// if we end up removing a value from the child node
 			if quadtree.remove(node.children[quadrantIdx], *quadRect, el) {
@@ -120,16 +343,7 @@ func (quadtree *BaseQuadTree) tryMerge(node *QuadNode) bool {
 		panic("only interior nodes can be merged")
 	}
 
-	totalEls := len(node.els)
-	for _, child := range node.children {
-		if !child.isLeaf() {
-			return false
-		}
-
-		totalEls += len(child.els)
-	}
-
-	if totalEls <= int(quadtree.threshold) {
+	if quadtree.isMergeable(node) {
 		for i, child := range node.children {
 			for _, childEl := range child.els {
 				node.els = append(node.els, childEl)
@@ -143,6 +357,20 @@ func (quadtree *BaseQuadTree) tryMerge(node *QuadNode) bool {
 	}
 }
 
+// whether the children of an interior node are leaves holding few enough elements to be merged in to it
+func (quadtree *BaseQuadTree) isMergeable(node *QuadNode) bool {
+	totalEls := len(node.els)
+	for _, child := range node.children {
+		if !child.isLeaf() {
+			return false
+		}
+
+		totalEls += len(child.els)
+	}
+
+	return totalEls <= int(quadtree.threshold)
+}
+
 func removeValue(node *QuadNode, el QuadElement) {
 	for i, otherEl := range node.els {
 		if el.Id == otherEl.Id {
@@ -154,6 +382,24 @@ func removeValue(node *QuadNode, el QuadElement) {
 	panic("unable to find the given element with id: " + el.Id)
 }
 
+// replaces the element in the node that has the same id as the given element
+func replaceValue(node *QuadNode, el QuadElement) {
+	for i, otherEl := range node.els {
+		if el.Id == otherEl.Id {
+			node.els[i] = el
+			return
+		}
+	}
+
+	panic("unable to find the given element with id: " + el.Id)
+}
+
+// whether an element with the given rect would be stored directly in an interior node with nodeRect
+func fitsNoQuadrant(nodeRect Rect, rect Rect) bool {
+	quadrantIdx, _ := QuadrantContaining(nodeRect, QuadElement{Rect: rect})
+	return quadrantIdx == -1
+}
+
 func (quadtree *BaseQuadTree) insert(node *QuadNode, nodeRect Rect, depth uint8, el QuadElement) {
 	if node == nil {
 		panic("node pointer was nil")
@@ -164,8 +410,9 @@ func (quadtree *BaseQuadTree) insert(node *QuadNode, nodeRect Rect, depth uint8,
 	}
 
 	if node.isLeaf() {
+		// each growth of the root adds a level above the original root, so it also raises the max depth.
 		// if were at max depth we want to insert the value to avoid infinite recursion
-		if depth >= quadtree.maxDepth || len(node.els) < int(quadtree.threshold) {
+		if depth >= quadtree.maxDepth+quadtree.growth || len(node.els) < int(quadtree.threshold) {
 			node.els = append(node.els, el)
 		} else {
 			node.split(nodeRect)
@@ -2,6 +2,7 @@ package quadtree
 
 import (
 	"fmt"
+	"math/rand"
 	"testing"
 
 	"github.com/TheRaizer/GolangGame/util"
@@ -252,22 +253,153 @@ func TestRemoveWithNilPanics(t *testing.T) {
 	quadtree.Remove(QuadElement{Rect{0, 0, 5, 5}, "id1"})
 }
 
-func TestRemovingUncontainedElPanics(t *testing.T) {
+func TestRemovingUncontainedElDoesNothing(t *testing.T) {
 	quadtree := BaseQuadTree{
 		threshold:  2,
 		maxDepth:   4,
 		globalRect: Rect{0, 0, 100, 100},
-		root:       &QuadNode{},
+		root: &QuadNode{
+			els: []QuadElement{{Rect{0, 0, 5, 5}, "id1"}},
+		},
 	}
 
-	defer func() {
-		r := recover()
+	require.NotPanics(t, func() {
+		quadtree.Remove(QuadElement{Rect{101, 101, 5, 5}, "id2"})
+	})
+	require.Equal(t, QuadNode{els: []QuadElement{{Rect{0, 0, 5, 5}, "id1"}}}, *quadtree.root)
+}
 
-		require.NotNil(t, r)
-		require.Equal(t, "the given quad does not contain the element rect", r)
-	}()
+func TestInsertOutsideGlobalRectGrowsRoot(t *testing.T) {
+	type TestExpected struct {
+		globalRect Rect
+		growth     uint8
+	}
+
+	const NAME string = "should grow the root to contain elements %+v"
+	getName := func(input []QuadElement) string {
+		return fmt.Sprintf(NAME, input)
+	}
+
+	var cases = []util.TestCase[[]QuadElement, TestExpected]{
+		{
+			Name:     getName,
+			Input:    []QuadElement{{Rect{120, 10, 5, 5}, "id1"}},
+			Expected: TestExpected{Rect{0, 0, 200, 200}, 1},
+		},
+		{
+			Name:     getName,
+			Input:    []QuadElement{{Rect{-10, -10, 5, 5}, "id1"}},
+			Expected: TestExpected{Rect{-100, -100, 200, 200}, 1},
+		},
+		{
+			Name: getName,
+			Input: []QuadElement{
+				{Rect{10, 10, 5, 5}, "id1"},
+				{Rect{20, 20, 5, 5}, "id2"},
+				{Rect{30, 30, 5, 5}, "id3"},
+				{Rect{-350, 10, 5, 5}, "id4"},
+			},
+			Expected: TestExpected{Rect{-700, 0, 800, 800}, 3},
+		},
+		{
+			Name:     getName,
+			Input:    []QuadElement{{Rect{90, 90, 20, 20}, "id1"}},
+			Expected: TestExpected{Rect{0, 0, 200, 200}, 1},
+		},
+	}
+
+	util.IterateTestCases(cases, t, func(testCase util.TestCase[[]QuadElement, TestExpected]) {
+		tree := NewQuadTree(2, 4, Rect{0, 0, 100, 100})
+
+		for _, el := range testCase.Input {
+			require.NotPanics(t, func() { tree.Insert(el) })
+		}
+
+		require.Equal(t, testCase.Expected.globalRect, tree.globalRect)
+		require.Equal(t, testCase.Expected.growth, tree.growth)
+		require.ElementsMatch(t, testCase.Input, tree.Query(tree.globalRect))
+	})
+}
+
+func TestGrowingKeepsOldRootAsQuadrant(t *testing.T) {
+	tree := NewQuadTree(2, 4, Rect{0, 0, 100, 100})
+	els := []QuadElement{
+		{Rect{10, 10, 5, 5}, "id1"},
+		{Rect{60, 10, 5, 5}, "id2"},
+		{Rect{10, 60, 5, 5}, "id3"},
+	}
+	for _, el := range els {
+		tree.Insert(el)
+	}
+	oldRoot := tree.root
+
+	tree.Insert(QuadElement{Rect{-50, -50, 5, 5}, "id4"})
+
+	// the old root lies to the south east of the element so it should become the SE quadrant
+	require.Equal(t, Rect{-100, -100, 200, 200}, tree.globalRect)
+	require.Same(t, oldRoot, tree.root.children[3])
+	require.Equal(t, []QuadElement{{Rect{-50, -50, 5, 5}, "id4"}}, tree.root.children[0].els)
+}
+
+func TestRemoveShrinksGrownRoot(t *testing.T) {
+	const NAME string = "should shrink the root back after removing elements %+v"
+	getName := func(input []QuadElement) string {
+		return fmt.Sprintf(NAME, input)
+	}
+
+	var cases = []util.TestCase[[]QuadElement, Rect]{
+		{
+			Name:     getName,
+			Input:    []QuadElement{{Rect{420, 10, 5, 5}, "id1"}},
+			Expected: Rect{0, 0, 100, 100},
+		},
+		{
+			Name: getName,
+			Input: []QuadElement{
+				{Rect{10, 10, 5, 5}, "id1"},
+				{Rect{20, 20, 5, 5}, "id2"},
+				{Rect{30, 30, 5, 5}, "id3"},
+				{Rect{-350, 10, 5, 5}, "id4"},
+			},
+			Expected: Rect{0, 0, 100, 100},
+		},
+		{
+			Name: getName,
+			Input: []QuadElement{
+				{Rect{10, 10, 5, 5}, "id1"},
+				{Rect{-10, -10, 5, 5}, "id2"},
+				{Rect{-90, 150, 5, 5}, "id3"},
+				{Rect{-350, -10, 5, 5}, "id4"},
+			},
+			Expected: Rect{0, 0, 100, 100},
+		},
+	}
+
+	util.IterateTestCases(cases, t, func(testCase util.TestCase[[]QuadElement, Rect]) {
+		tree := NewQuadTree(2, 4, Rect{0, 0, 100, 100})
+
+		for _, el := range testCase.Input {
+			tree.Insert(el)
+		}
+
+		for i := len(testCase.Input) - 1; i >= 0; i-- {
+			require.NotPanics(t, func() { tree.Remove(testCase.Input[i]) })
+			require.ElementsMatch(t, testCase.Input[:i], tree.Query(tree.globalRect))
+		}
 
-	quadtree.Remove(QuadElement{Rect{101, 101, 5, 5}, "id1"})
+		require.Equal(t, testCase.Expected, tree.globalRect)
+		require.Equal(t, uint8(0), tree.growth)
+	})
+}
+
+func TestRemoveShouldNotShrinkWhileElementsOutsideBaseRect(t *testing.T) {
+	tree := NewQuadTree(2, 4, Rect{0, 0, 100, 100})
+	tree.Insert(QuadElement{Rect{10, 10, 5, 5}, "id1"})
+	tree.Insert(QuadElement{Rect{150, 150, 5, 5}, "id2"})
+	tree.Remove(QuadElement{Rect{10, 10, 5, 5}, "id1"})
+
+	require.Equal(t, Rect{0, 0, 200, 200}, tree.globalRect)
+	require.Equal(t, []QuadElement{{Rect{150, 150, 5, 5}, "id2"}}, tree.Query(tree.globalRect))
 }
 
 func TestQuery(t *testing.T) {
@@ -336,6 +468,37 @@ func TestQuery(t *testing.T) {
 				{Rect{0, 0, 3, 3}, "id2"},
 			},
 		},
+		{ // elements stored in interior nodes
+			Name: getName,
+			Input: TestInput{
+				BaseQuadTree{
+					threshold:  2,
+					maxDepth:   4,
+					globalRect: Rect{0, 0, 80, 80},
+					root: &QuadNode{
+						children: [4]*QuadNode{
+							{
+								els: []QuadElement{
+									{Rect{0, 0, 5, 5}, "id1"},
+								},
+							},
+							{},
+							{},
+							{},
+						},
+						els: []QuadElement{
+							{Rect{35, 35, 10, 10}, "id2"},
+							{Rect{70, 35, 5, 10}, "id3"},
+						},
+					},
+				},
+				Rect{0, 0, 40, 40},
+			},
+			Expected: []QuadElement{
+				{Rect{0, 0, 5, 5}, "id1"},
+				{Rect{35, 35, 10, 10}, "id2"},
+			},
+		},
 	}
 
 	util.IterateTestCases(cases, t, func(testCase util.TestCase[TestInput, []QuadElement]) {
@@ -361,3 +524,260 @@ func TestQueryWithNilPanics(t *testing.T) {
 
 	quadtree.Query(Rect{101, 101, 5, 5})
 }
+
+func TestMove(t *testing.T) {
+	type TestInput struct {
+		tree    BaseQuadTree
+		id      string
+		oldRect Rect
+		newRect Rect
+	}
+
+	const NAME string = "should move element %s from %+v to %+v correctly"
+	getName := func(input TestInput) string {
+		return fmt.Sprintf(NAME, input.id, input.oldRect, input.newRect)
+	}
+
+	var cases = []util.TestCase[TestInput, QuadNode]{
+		{ // moving within a leaf
+			Name: getName,
+			Input: TestInput{
+				BaseQuadTree{
+					threshold:  2,
+					maxDepth:   4,
+					globalRect: Rect{0, 0, 100, 100},
+					root: &QuadNode{
+						els: []QuadElement{
+							{Rect{0, 0, 5, 5}, "id1"},
+							{Rect{60, 60, 5, 5}, "id2"},
+						},
+					},
+				},
+				"id1", Rect{0, 0, 5, 5}, Rect{80, 10, 5, 5},
+			},
+			Expected: QuadNode{
+				els: []QuadElement{
+					{Rect{80, 10, 5, 5}, "id1"},
+					{Rect{60, 60, 5, 5}, "id2"},
+				},
+			},
+		},
+		{ // moving from one quadrant to another
+			Name: getName,
+			Input: TestInput{
+				BaseQuadTree{
+					threshold:  2,
+					maxDepth:   4,
+					globalRect: Rect{0, 0, 100, 100},
+					root: &QuadNode{
+						children: [4]*QuadNode{
+							{
+								els: []QuadElement{
+									{Rect{0, 0, 5, 5}, "id1"},
+									{Rect{10, 10, 5, 5}, "id2"},
+								},
+							},
+							{},
+							{},
+							{
+								els: []QuadElement{
+									{Rect{60, 60, 5, 5}, "id3"},
+								},
+							},
+						},
+					},
+				},
+				"id1", Rect{0, 0, 5, 5}, Rect{70, 70, 5, 5},
+			},
+			Expected: QuadNode{
+				children: [4]*QuadNode{
+					{
+						els: []QuadElement{
+							{Rect{10, 10, 5, 5}, "id2"},
+						},
+					},
+					{},
+					{},
+					{
+						els: []QuadElement{
+							{Rect{60, 60, 5, 5}, "id3"},
+							{Rect{70, 70, 5, 5}, "id1"},
+						},
+					},
+				},
+			},
+		},
+		{ // moving onto the border of two quadrants
+			Name: getName,
+			Input: TestInput{
+				BaseQuadTree{
+					threshold:  2,
+					maxDepth:   4,
+					globalRect: Rect{0, 0, 100, 100},
+					root: &QuadNode{
+						children: [4]*QuadNode{
+							{
+								els: []QuadElement{
+									{Rect{0, 0, 5, 5}, "id1"},
+									{Rect{10, 10, 5, 5}, "id2"},
+								},
+							},
+							{},
+							{},
+							{
+								els: []QuadElement{
+									{Rect{60, 60, 5, 5}, "id3"},
+								},
+							},
+						},
+					},
+				},
+				"id1", Rect{0, 0, 5, 5}, Rect{48, 48, 5, 5},
+			},
+			Expected: QuadNode{
+				children: [4]*QuadNode{
+					{
+						els: []QuadElement{
+							{Rect{10, 10, 5, 5}, "id2"},
+						},
+					},
+					{},
+					{},
+					{
+						els: []QuadElement{
+							{Rect{60, 60, 5, 5}, "id3"},
+						},
+					},
+				},
+				els: []QuadElement{{Rect{48, 48, 5, 5}, "id1"}},
+			},
+		},
+		{ // moving out of a quadrant lets it merge back into the root
+			Name: getName,
+			Input: TestInput{
+				BaseQuadTree{
+					threshold:  2,
+					maxDepth:   4,
+					globalRect: Rect{0, 0, 100, 100},
+					root: &QuadNode{
+						children: [4]*QuadNode{
+							{
+								els: []QuadElement{
+									{Rect{10, 10, 5, 5}, "id2"},
+								},
+							},
+							{},
+							{},
+							{},
+						},
+						els: []QuadElement{{Rect{48, 48, 5, 5}, "id1"}},
+					},
+				},
+				"id2", Rect{10, 10, 5, 5}, Rect{10, 20, 5, 5},
+			},
+			Expected: QuadNode{
+				children: [4]*QuadNode{
+					{
+						els: []QuadElement{
+							{Rect{10, 20, 5, 5}, "id2"},
+						},
+					},
+					{},
+					{},
+					{},
+				},
+				els: []QuadElement{{Rect{48, 48, 5, 5}, "id1"}},
+			},
+		},
+	}
+
+	util.IterateTestCases(cases, t, func(testCase util.TestCase[TestInput, QuadNode]) {
+		testCase.Input.tree.Move(testCase.Input.id, testCase.Input.oldRect, testCase.Input.newRect)
+		require.Equal(t, testCase.Expected, *testCase.Input.tree.root)
+	})
+}
+
+func TestMoveShouldKeepElInSameLeaf(t *testing.T) {
+	tree := NewQuadTree(2, 4, Rect{0, 0, 100, 100})
+	tree.Insert(QuadElement{Rect{0, 0, 5, 5}, "id1"})
+	tree.Insert(QuadElement{Rect{10, 10, 5, 5}, "id2"})
+	tree.Insert(QuadElement{Rect{70, 70, 5, 5}, "id3"})
+
+	leaf := tree.root.children[0]
+	tree.Move("id1", Rect{0, 0, 5, 5}, Rect{1, 2, 5, 5})
+
+	require.Same(t, leaf, tree.root.children[0])
+	require.ElementsMatch(t, []QuadElement{{Rect{1, 2, 5, 5}, "id1"}, {Rect{10, 10, 5, 5}, "id2"}}, leaf.els)
+}
+
+func TestMoveOutsideGlobalRectShouldGrowAndShrink(t *testing.T) {
+	tree := NewQuadTree(2, 4, Rect{0, 0, 100, 100})
+	tree.Insert(QuadElement{Rect{0, 0, 5, 5}, "id1"})
+	tree.Insert(QuadElement{Rect{10, 10, 5, 5}, "id2"})
+	tree.Insert(QuadElement{Rect{70, 70, 5, 5}, "id3"})
+
+	require.NotPanics(t, func() { tree.Move("id3", Rect{70, 70, 5, 5}, Rect{170, 70, 5, 5}) })
+	require.Equal(t, Rect{0, 0, 200, 200}, tree.globalRect)
+	require.ElementsMatch(t, []QuadElement{{Rect{170, 70, 5, 5}, "id3"}}, tree.Query(Rect{100, 0, 100, 100}))
+
+	tree.Move("id3", Rect{170, 70, 5, 5}, Rect{70, 70, 5, 5})
+	require.Equal(t, Rect{0, 0, 100, 100}, tree.globalRect)
+	require.ElementsMatch(t, []QuadElement{{Rect{70, 70, 5, 5}, "id3"}}, tree.Query(Rect{50, 50, 50, 50}))
+}
+
+func TestMoveShouldMatchBruteForce(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	tree := NewQuadTree(3, 5, Rect{0, 0, 400, 400})
+	els := make([]QuadElement, 0)
+
+	for i := 0; i < 60; i++ {
+		el := QuadElement{Rect{float32(random.Int31n(390)), float32(random.Int31n(390)), 10, 10}, fmt.Sprintf("id%d", i)}
+		els = append(els, el)
+		tree.Insert(el)
+	}
+
+	for step := 0; step < 500; step++ {
+		i := random.Intn(len(els))
+		newRect := Rect{els[i].X + float32(random.Int31n(41)) - 20, els[i].Y + float32(random.Int31n(41)) - 20, 10, 10}
+
+		tree.Move(els[i].Id, els[i].Rect, newRect)
+		els[i].Rect = newRect
+
+		require.ElementsMatch(t, els, tree.Query(tree.globalRect))
+	}
+}
+
+func TestVisitQueryShouldStopEarly(t *testing.T) {
+	tree := NewQuadTree(2, 4, Rect{0, 0, 100, 100})
+	for i := 0; i < 10; i++ {
+		tree.Insert(QuadElement{Rect{float32(i) * 10, float32(i) * 10, 5, 5}, fmt.Sprintf("id%d", i)})
+	}
+
+	visited := 0
+	tree.VisitQuery(Rect{0, 0, 100, 100}, nil, func(el QuadElement) bool {
+		visited++
+		return visited < 3
+	})
+
+	require.Equal(t, 3, visited)
+}
+
+func TestQueryAppendShouldFilterIntoBuffer(t *testing.T) {
+	tree := NewQuadTree(2, 4, Rect{0, 0, 100, 100})
+	for i := 0; i < 10; i++ {
+		tree.Insert(QuadElement{Rect{float32(i) * 10, float32(i) * 10, 5, 5}, fmt.Sprintf("id%d", i)})
+	}
+	onlyEven := func(el QuadElement) bool {
+		return int(el.X/10)%2 == 0
+	}
+	buf := make([]QuadElement, 0, 10)
+
+	buf = tree.QueryAppend(Rect{0, 0, 45, 45}, onlyEven, buf)
+	require.ElementsMatch(t, []QuadElement{{Rect{0, 0, 5, 5}, "id0"}, {Rect{20, 20, 5, 5}, "id2"}, {Rect{40, 40, 5, 5}, "id4"}}, buf)
+
+	allocs := testing.AllocsPerRun(10, func() {
+		buf = tree.QueryAppend(Rect{0, 0, 100, 100}, onlyEven, buf[:0])
+	})
+	require.Zero(t, allocs)
+	require.Len(t, buf, 5)
+}
@@ -1,17 +1,40 @@
 package quadtree
 
 import (
+	"math"
+
 	"github.com/TheRaizer/GolangGame/util"
 	"github.com/veandco/go-sdl2/sdl"
 )
 
-type Rect sdl.Rect
+// Positions of game objects are float32, so rects keep float32 coordinates to avoid losing sub-pixel movement.
+// Convert to an sdl.Rect using ToSDL only when drawing.
+type Rect sdl.FRect
+
+// Converts an sdl.Rect in to a Rect
+func RectFromSDL(sdlRect sdl.Rect) Rect {
+	return Rect{X: float32(sdlRect.X), Y: float32(sdlRect.Y), W: float32(sdlRect.W), H: float32(sdlRect.H)}
+}
 
-func (rect *Rect) Center() util.Vec2[int32] {
+// Converts the rect in to an sdl.Rect for drawing by rounding it to the nearest pixels
+func (rect *Rect) ToSDL() sdl.Rect {
+	x := int32(math.Round(float64(rect.X)))
+	y := int32(math.Round(float64(rect.Y)))
+
+	// round the edges rather than the size so that adjacent rects do not leave gaps between them
+	return sdl.Rect{
+		X: x,
+		Y: y,
+		W: int32(math.Round(float64(rect.Right()))) - x,
+		H: int32(math.Round(float64(rect.Bottom()))) - y,
+	}
+}
+
+func (rect *Rect) Center() util.Vec2[float32] {
 	centerX := rect.X + rect.W/2
 	centerY := rect.Y + rect.H/2
 
-	return util.Vec2[int32]{X: centerX, Y: centerY}
+	return util.Vec2[float32]{X: centerX, Y: centerY}
 }
 
 func (rect *Rect) Contains(otherRect Rect) bool {
@@ -29,10 +52,54 @@ func (rect *Rect) Intersects(otherRect Rect) bool {
 	return !(otherRect.Right() <= rect.X || otherRect.Y >= rect.Bottom() || otherRect.X >= rect.Right() || otherRect.Bottom() <= rect.Y)
 }
 
-func (rect *Rect) Right() int32 {
+// Returns the smallest rect that contains both rects
+func (rect *Rect) Union(otherRect Rect) Rect {
+	x := min(rect.X, otherRect.X)
+	y := min(rect.Y, otherRect.Y)
+
+	return Rect{
+		X: x,
+		Y: y,
+		W: max(rect.Right(), otherRect.Right()) - x,
+		H: max(rect.Bottom(), otherRect.Bottom()) - y,
+	}
+}
+
+// Returns the region where both rects overlap, and false if they do not intersect
+func (rect *Rect) Intersection(otherRect Rect) (Rect, bool) {
+	if !rect.Intersects(otherRect) {
+		return Rect{}, false
+	}
+
+	x := max(rect.X, otherRect.X)
+	y := max(rect.Y, otherRect.Y)
+
+	return Rect{
+		X: x,
+		Y: y,
+		W: min(rect.Right(), otherRect.Right()) - x,
+		H: min(rect.Bottom(), otherRect.Bottom()) - y,
+	}, true
+}
+
+// Returns the area of the region where both rects overlap
+func (rect *Rect) IntersectionArea(otherRect Rect) float32 {
+	intersection, ok := rect.Intersection(otherRect)
+	if !ok {
+		return 0
+	}
+
+	return intersection.Area()
+}
+
+func (rect *Rect) Area() float32 {
+	return rect.W * rect.H
+}
+
+func (rect *Rect) Right() float32 {
 	return rect.X + rect.W
 }
 
-func (rect *Rect) Bottom() int32 {
+func (rect *Rect) Bottom() float32 {
 	return rect.Y + rect.H
 }
@@ -6,6 +6,7 @@ import (
 
 	"github.com/TheRaizer/GolangGame/util"
 	"github.com/stretchr/testify/require"
+	"github.com/veandco/go-sdl2/sdl"
 )
 
 func TestCenter(t *testing.T) {
@@ -14,19 +15,19 @@ func TestCenter(t *testing.T) {
 		return fmt.Sprintf(NAME, input)
 	}
 
-	var cases = []util.TestCase[Rect, util.Vec2[int32]]{
+	var cases = []util.TestCase[Rect, util.Vec2[float32]]{
 		{
 			Name:  getName,
-			Input: Rect{0, 0, 10, 10}, Expected: util.Vec2[int32]{X: 5, Y: 5},
+			Input: Rect{0, 0, 10, 10}, Expected: util.Vec2[float32]{X: 5, Y: 5},
 		},
 		{
 			Name:  getName,
-			Input: Rect{5, 3, 17, 14}, Expected: util.Vec2[int32]{X: 13, Y: 10},
+			Input: Rect{5, 3, 17, 14}, Expected: util.Vec2[float32]{X: 13.5, Y: 10},
 		},
 	}
 
 	util.IterateTestCases(cases, t,
-		func(testCase util.TestCase[Rect, util.Vec2[int32]]) {
+		func(testCase util.TestCase[Rect, util.Vec2[float32]]) {
 			center := testCase.Input.Center()
 			require.Equal(t, testCase.Expected, center)
 		})
@@ -148,7 +149,7 @@ func TestRight(t *testing.T) {
 		return fmt.Sprintf(NAME, input)
 	}
 
-	var cases = []util.TestCase[Rect, int32]{
+	var cases = []util.TestCase[Rect, float32]{
 		{
 			Name:  getName,
 			Input: Rect{0, 0, 10, 10}, Expected: 10,
@@ -160,7 +161,7 @@ func TestRight(t *testing.T) {
 	}
 
 	util.IterateTestCases(cases, t,
-		func(testCase util.TestCase[Rect, int32]) {
+		func(testCase util.TestCase[Rect, float32]) {
 			right := testCase.Input.Right()
 			require.Equal(t, testCase.Expected, right)
 		})
@@ -173,7 +174,7 @@ func TestBottom(t *testing.T) {
 		return fmt.Sprintf(NAME, input)
 	}
 
-	var cases = []util.TestCase[Rect, int32]{
+	var cases = []util.TestCase[Rect, float32]{
 		{
 			Name:  getName,
 			Input: Rect{0, 0, 10, 10}, Expected: 10,
@@ -185,8 +186,119 @@ func TestBottom(t *testing.T) {
 	}
 
 	util.IterateTestCases(cases, t,
-		func(testCase util.TestCase[Rect, int32]) {
+		func(testCase util.TestCase[Rect, float32]) {
 			bottom := testCase.Input.Bottom()
 			require.Equal(t, testCase.Expected, bottom)
 		})
 }
+
+func TestUnion(t *testing.T) {
+	type TestInput struct {
+		rect      Rect
+		otherRect Rect
+	}
+
+	const NAME string = "should return union of Rect%+v and Rect%+v"
+	getName := func(input TestInput) string {
+		return fmt.Sprintf(NAME, input.rect, input.otherRect)
+	}
+
+	var cases = []util.TestCase[TestInput, Rect]{
+		{
+			Name:     getName,
+			Input:    TestInput{Rect{0, 0, 10, 10}, Rect{2, 2, 4, 4}},
+			Expected: Rect{0, 0, 10, 10},
+		},
+		{
+			Name:     getName,
+			Input:    TestInput{Rect{0, 0, 10, 10}, Rect{15, -5, 5, 5}},
+			Expected: Rect{0, -5, 20, 15},
+		},
+		{
+			Name:     getName,
+			Input:    TestInput{Rect{0.5, 0.25, 1, 1}, Rect{2, 2, 0.5, 0.5}},
+			Expected: Rect{0.5, 0.25, 2, 2.25},
+		},
+	}
+
+	util.IterateTestCases(cases, t, func(testCase util.TestCase[TestInput, Rect]) {
+		union := testCase.Input.rect.Union(testCase.Input.otherRect)
+		require.Equal(t, testCase.Expected, union)
+	})
+}
+
+func TestIntersection(t *testing.T) {
+	type TestInput struct {
+		rect      Rect
+		otherRect Rect
+	}
+
+	type Expected struct {
+		intersection Rect
+		ok           bool
+	}
+
+	const NAME string = "should return intersection of Rect%+v and Rect%+v"
+	getName := func(input TestInput) string {
+		return fmt.Sprintf(NAME, input.rect, input.otherRect)
+	}
+
+	var cases = []util.TestCase[TestInput, Expected]{
+		{
+			Name:     getName,
+			Input:    TestInput{Rect{0, 0, 10, 10}, Rect{5, 5, 10, 10}},
+			Expected: Expected{Rect{5, 5, 5, 5}, true},
+		},
+		{
+			Name:     getName,
+			Input:    TestInput{Rect{0, 0, 10, 10}, Rect{9.5, 2, 4, 4}},
+			Expected: Expected{Rect{9.5, 2, 0.5, 4}, true},
+		},
+		{
+			Name:     getName,
+			Input:    TestInput{Rect{0, 0, 10, 10}, Rect{10, 0, 4, 4}},
+			Expected: Expected{Rect{}, false},
+		},
+	}
+
+	util.IterateTestCases(cases, t, func(testCase util.TestCase[TestInput, Expected]) {
+		intersection, ok := testCase.Input.rect.Intersection(testCase.Input.otherRect)
+		require.Equal(t, testCase.Expected.ok, ok)
+		require.Equal(t, testCase.Expected.intersection, intersection)
+		require.Equal(t, testCase.Expected.intersection.Area(), testCase.Input.rect.IntersectionArea(testCase.Input.otherRect))
+	})
+}
+
+func TestToSDL(t *testing.T) {
+	const NAME string = "should round Rect%+v to the nearest pixels"
+	getName := func(input Rect) string {
+		return fmt.Sprintf(NAME, input)
+	}
+
+	var cases = []util.TestCase[Rect, sdl.Rect]{
+		{
+			Name:     getName,
+			Input:    Rect{1, 2, 3, 4},
+			Expected: sdl.Rect{X: 1, Y: 2, W: 3, H: 4},
+		},
+		{
+			Name:     getName,
+			Input:    Rect{0.4, 0.6, 10, 10},
+			Expected: sdl.Rect{X: 0, Y: 1, W: 10, H: 10},
+		},
+		{
+			// the right edge at 10.6 rounds to 11 so the width grows to keep the edge in place
+			Name:     getName,
+			Input:    Rect{0.4, 0, 10.2, 10},
+			Expected: sdl.Rect{X: 0, Y: 0, W: 11, H: 10},
+		},
+	}
+
+	util.IterateTestCases(cases, t, func(testCase util.TestCase[Rect, sdl.Rect]) {
+		require.Equal(t, testCase.Expected, testCase.Input.ToSDL())
+	})
+}
+
+func TestRectFromSDL(t *testing.T) {
+	require.Equal(t, Rect{1, 2, 3, 4}, RectFromSDL(sdl.Rect{X: 1, Y: 2, W: 3, H: 4}))
+}
@@ -0,0 +1,89 @@
+package quadtree
+
+import "sync"
+
+// Wraps a QuadTree so that it can be used from several goroutines at once.
+// Queries share a read lock and run in parallel, while modifications take the write lock.
+// Visitors and filters run while the read lock is held, so they must not modify the tree.
+type SyncQuadTree struct {
+	tree QuadTree
+	lock sync.RWMutex
+}
+
+func NewSyncQuadTree(tree QuadTree) *SyncQuadTree {
+	return &SyncQuadTree{tree: tree}
+}
+
+func (syncTree *SyncQuadTree) Insert(el QuadElement) {
+	syncTree.lock.Lock()
+	defer syncTree.lock.Unlock()
+
+	syncTree.tree.Insert(el)
+}
+
+func (syncTree *SyncQuadTree) Query(hitbox Rect) []QuadElement {
+	syncTree.lock.RLock()
+	defer syncTree.lock.RUnlock()
+
+	return syncTree.tree.Query(hitbox)
+}
+
+func (syncTree *SyncQuadTree) QueryAppend(hitbox Rect, filter Filter, buf []QuadElement) []QuadElement {
+	syncTree.lock.RLock()
+	defer syncTree.lock.RUnlock()
+
+	return syncTree.tree.QueryAppend(hitbox, filter, buf)
+}
+
+func (syncTree *SyncQuadTree) VisitQuery(hitbox Rect, filter Filter, visitor Visitor) {
+	syncTree.lock.RLock()
+	defer syncTree.lock.RUnlock()
+
+	syncTree.tree.VisitQuery(hitbox, filter, visitor)
+}
+
+func (syncTree *SyncQuadTree) Remove(el QuadElement) {
+	syncTree.lock.Lock()
+	defer syncTree.lock.Unlock()
+
+	syncTree.tree.Remove(el)
+}
+
+func (syncTree *SyncQuadTree) Move(id string, oldRect Rect, newRect Rect) {
+	syncTree.lock.Lock()
+	defer syncTree.lock.Unlock()
+
+	syncTree.tree.Move(id, oldRect, newRect)
+}
+
+// Runs a query for each hitbox, spread over the given number of goroutines.
+// The results of hitboxes[i] are appended to results[i][:0], so passing the results of the
+// previous call back in reuses their slices. The tree and filter must be safe for concurrent
+// reads, which holds for any tree that is not modified while the queries run, or a SyncQuadTree.
+func ParallelQuery(tree QuadTree, hitboxes []Rect, filter Filter, results [][]QuadElement, workers int) [][]QuadElement {
+	for len(results) < len(hitboxes) {
+		results = append(results, nil)
+	}
+	results = results[:len(hitboxes)]
+
+	workers = max(min(workers, len(hitboxes)), 1)
+	chunkSize := (len(hitboxes) + workers - 1) / workers
+
+	var wg sync.WaitGroup
+	for start := 0; start < len(hitboxes); start += chunkSize {
+		end := min(start+chunkSize, len(hitboxes))
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			// each goroutine only writes to its own range of results
+			for i := start; i < end; i++ {
+				results[i] = tree.QueryAppend(hitboxes[i], filter, results[i][:0])
+			}
+		}()
+	}
+	wg.Wait()
+
+	return results
+}
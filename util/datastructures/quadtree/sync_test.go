@@ -0,0 +1,100 @@
+package quadtree
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+	"testing"
+
+	"github.com/TheRaizer/GolangGame/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// run with -race to detect unsynchronized access
+func TestSyncQuadTreeShouldAllowConcurrentUse(t *testing.T) {
+	base := NewQuadTree(4, 5, Rect{0, 0, 800, 600})
+	syncTree := NewSyncQuadTree(&base)
+
+	for i := 0; i < 50; i++ {
+		syncTree.Insert(QuadElement{Rect{float32(i) * 16, 500, 16, 16}, fmt.Sprintf("static%d", i)})
+	}
+
+	var wg sync.WaitGroup
+	for worker := 0; worker < 4; worker++ {
+		wg.Add(2)
+
+		// a writer moving its own element around, including outside of the global rect
+		go func() {
+			defer wg.Done()
+			random := rand.New(rand.NewSource(int64(worker)))
+			id := fmt.Sprintf("mover%d", worker)
+			rect := Rect{100, 100, 20, 20}
+			syncTree.Insert(QuadElement{rect, id})
+
+			for step := 0; step < 200; step++ {
+				newRect := Rect{rect.X + float32(random.Int31n(81)) - 40, rect.Y + float32(random.Int31n(81)) - 40, 20, 20}
+				syncTree.Move(id, rect, newRect)
+				rect = newRect
+			}
+
+			syncTree.Remove(QuadElement{rect, id})
+		}()
+
+		// a reader querying the static elements
+		go func() {
+			defer wg.Done()
+			buf := make([]QuadElement, 0)
+
+			for step := 0; step < 200; step++ {
+				buf = syncTree.QueryAppend(Rect{0, 500, 800, 16}, func(el QuadElement) bool { return el.Y == 500 && el.H == 16 }, buf[:0])
+				assert.Len(t, buf, 50)
+			}
+		}()
+	}
+	wg.Wait()
+
+	require.Len(t, syncTree.Query(Rect{-10000, -10000, 20000, 20000}), 50)
+	require.NoError(t, base.Validate())
+}
+
+func TestParallelQueryShouldMatchSequentialQueries(t *testing.T) {
+	const NAME string = "should match sequential queries when using %d workers"
+	getName := func(input int) string {
+		return fmt.Sprintf(NAME, input)
+	}
+
+	cases := []util.TestCase[int, struct{}]{
+		{Name: getName, Input: 0},
+		{Name: getName, Input: 1},
+		{Name: getName, Input: 3},
+		{Name: getName, Input: 8},
+		{Name: getName, Input: 100},
+	}
+
+	random := rand.New(rand.NewSource(4))
+	tree := NewQuadTree(4, 5, Rect{0, 0, 800, 600})
+	for i := 0; i < 100; i++ {
+		tree.Insert(QuadElement{Rect{float32(random.Int31n(780)), float32(random.Int31n(580)), 20, 20}, fmt.Sprintf("id%d", i)})
+	}
+
+	hitboxes := make([]Rect, 0)
+	for i := 0; i < 40; i++ {
+		hitboxes = append(hitboxes, Rect{float32(random.Int31n(700)), float32(random.Int31n(500)), 100, 100})
+	}
+
+	util.IterateTestCases(cases, t, func(testCase util.TestCase[int, struct{}]) {
+		// reuse results that are too long to ensure they are cleared
+		results := make([][]QuadElement, 50)
+		for i := range results {
+			results[i] = []QuadElement{{Rect{0, 0, 1, 1}, "stale"}}
+		}
+
+		results = ParallelQuery(&tree, hitboxes, nil, results, testCase.Input)
+
+		require.Len(t, results, len(hitboxes))
+		for i, hitbox := range hitboxes {
+			require.ElementsMatch(t, tree.Query(hitbox), results[i])
+		}
+	})
+}
@@ -0,0 +1,147 @@
+package spatialhash
+
+import (
+	"math"
+
+	"github.com/TheRaizer/GolangGame/util"
+	"github.com/TheRaizer/GolangGame/util/datastructures/quadtree"
+)
+
+// A uniform grid of square cells that are only allocated once an element overlaps them.
+// Each element is stored in every cell its rect overlaps, so the world is unbounded.
+type SpatialHash struct {
+	cellSize float32
+	cells    map[cellKey][]quadtree.QuadElement
+}
+
+type cellKey struct {
+	X, Y int32
+}
+
+func NewSpatialHash(cellSize float32) SpatialHash {
+	if cellSize <= 0 {
+		panic("cell size must be positive")
+	}
+
+	return SpatialHash{
+		cellSize: cellSize,
+		cells:    make(map[cellKey][]quadtree.QuadElement),
+	}
+}
+
+// Inserts an element into every cell that its rect overlaps
+func (hash *SpatialHash) Insert(el quadtree.QuadElement) {
+	hash.forEachCell(el.Rect, func(key cellKey) {
+		hash.cells[key] = append(hash.cells[key], el)
+	})
+}
+
+// Queries for elements that lie inside the given rect
+func (hash *SpatialHash) Query(hitbox quadtree.Rect) []quadtree.QuadElement {
+	return hash.QueryAppend(hitbox, nil, nil)
+}
+
+// Appends the elements that lie inside the given rect and pass the filter to buf
+func (hash *SpatialHash) QueryAppend(hitbox quadtree.Rect, filter quadtree.Filter, buf []quadtree.QuadElement) []quadtree.QuadElement {
+	hash.VisitQuery(hitbox, filter, func(el quadtree.QuadElement) bool {
+		buf = append(buf, el)
+		return true
+	})
+
+	return buf
+}
+
+// Calls the visitor with each element that lies inside the given rect and passes the filter,
+// until the visitor returns false.
+// Elements spanning several cells are only visited once, from the cell holding the
+// top left corner of their overlap with the hitbox.
+func (hash *SpatialHash) VisitQuery(hitbox quadtree.Rect, filter quadtree.Filter, visitor quadtree.Visitor) {
+	bounds := hash.cellBounds(hitbox)
+
+	for x := bounds[0].X; x <= bounds[1].X; x++ {
+		for y := bounds[0].Y; y <= bounds[1].Y; y++ {
+			for _, el := range hash.cells[cellKey{x, y}] {
+				if hash.cellCoord(max(el.X, hitbox.X)) != x || hash.cellCoord(max(el.Y, hitbox.Y)) != y {
+					continue
+				}
+
+				if hitbox.Intersects(el.Rect) && (filter == nil || filter(el)) && !visitor(el) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Removes an element from every cell that its rect overlaps
+func (hash *SpatialHash) Remove(el quadtree.QuadElement) {
+	hash.forEachCell(el.Rect, func(key cellKey) {
+		cell := hash.cells[key]
+
+		for i, otherEl := range cell {
+			if otherEl.Id == el.Id {
+				cell = util.Slice[quadtree.QuadElement](cell).RemoveIdx(i)
+				break
+			}
+		}
+
+		if len(cell) == 0 {
+			delete(hash.cells, key)
+		} else {
+			hash.cells[key] = cell
+		}
+	})
+}
+
+// Moves an element from oldRect to newRect.
+// When both rects overlap the same cells the element is only updated in place.
+func (hash *SpatialHash) Move(id string, oldRect quadtree.Rect, newRect quadtree.Rect) {
+	if hash.cellBounds(oldRect) != hash.cellBounds(newRect) {
+		hash.Remove(quadtree.QuadElement{Rect: oldRect, Id: id})
+		hash.Insert(quadtree.QuadElement{Rect: newRect, Id: id})
+		return
+	}
+
+	hash.forEachCell(newRect, func(key cellKey) {
+		cell := hash.cells[key]
+
+		for i := range cell {
+			if cell[i].Id == id {
+				cell[i].Rect = newRect
+				break
+			}
+		}
+	})
+}
+
+// calls fn with the key of every cell that the given rect overlaps
+func (hash *SpatialHash) forEachCell(rect quadtree.Rect, fn func(key cellKey)) {
+	bounds := hash.cellBounds(rect)
+
+	for x := bounds[0].X; x <= bounds[1].X; x++ {
+		for y := bounds[0].Y; y <= bounds[1].Y; y++ {
+			fn(cellKey{x, y})
+		}
+	}
+}
+
+// returns the keys of the top left and bottom right cells that the given rect overlaps
+func (hash *SpatialHash) cellBounds(rect quadtree.Rect) [2]cellKey {
+	topLeft := cellKey{hash.cellCoord(rect.X), hash.cellCoord(rect.Y)}
+
+	// the right and bottom edges are exclusive, but empty rects still belong to the cell they lie in
+	return [2]cellKey{
+		topLeft,
+		{max(hash.lastCellCoord(rect.Right()), topLeft.X), max(hash.lastCellCoord(rect.Bottom()), topLeft.Y)},
+	}
+}
+
+// returns the index of the cell containing the given coordinate
+func (hash *SpatialHash) cellCoord(coord float32) int32 {
+	return int32(math.Floor(float64(coord / hash.cellSize)))
+}
+
+// returns the index of the last cell before the given exclusive edge
+func (hash *SpatialHash) lastCellCoord(edge float32) int32 {
+	return int32(math.Ceil(float64(edge/hash.cellSize))) - 1
+}
@@ -0,0 +1,201 @@
+package spatialhash
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/TheRaizer/GolangGame/util"
+	"github.com/TheRaizer/GolangGame/util/datastructures/quadtree"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInsertShouldStoreElInOverlappedCells(t *testing.T) {
+	const NAME string = "should store element %+v in the cells it overlaps"
+	getName := func(input quadtree.QuadElement) string {
+		return fmt.Sprintf(NAME, input)
+	}
+
+	cases := []util.TestCase[quadtree.QuadElement, []cellKey]{
+		{
+			Name:     getName,
+			Input:    quadtree.QuadElement{Rect: quadtree.Rect{X: 2, Y: 3, W: 5, H: 5}, Id: "id1"},
+			Expected: []cellKey{{0, 0}},
+		},
+		{
+			Name:     getName,
+			Input:    quadtree.QuadElement{Rect: quadtree.Rect{X: 5, Y: 0, W: 10, H: 10}, Id: "id1"},
+			Expected: []cellKey{{0, 0}, {1, 0}},
+		},
+		{
+			Name:     getName,
+			Input:    quadtree.QuadElement{Rect: quadtree.Rect{X: -5, Y: -15, W: 10, H: 10}, Id: "id1"},
+			Expected: []cellKey{{-1, -2}, {0, -2}, {-1, -1}, {0, -1}},
+		},
+		{
+			Name:     getName,
+			Input:    quadtree.QuadElement{Rect: quadtree.Rect{X: -10, Y: 10, W: 0, H: 0}, Id: "id1"},
+			Expected: []cellKey{{-1, 1}},
+		},
+	}
+
+	util.IterateTestCases(cases, t, func(testCase util.TestCase[quadtree.QuadElement, []cellKey]) {
+		hash := NewSpatialHash(10)
+		hash.Insert(testCase.Input)
+
+		keys := make([]cellKey, 0)
+		for key, cell := range hash.cells {
+			require.Equal(t, []quadtree.QuadElement{testCase.Input}, cell)
+			keys = append(keys, key)
+		}
+
+		require.ElementsMatch(t, testCase.Expected, keys)
+	})
+}
+
+func TestQueryShouldReturnEachIntersectingElOnce(t *testing.T) {
+	els := []quadtree.QuadElement{
+		{Rect: quadtree.Rect{X: 0, Y: 0, W: 5, H: 5}, Id: "id1"},
+		{Rect: quadtree.Rect{X: 5, Y: 5, W: 30, H: 30}, Id: "id2"},
+		{Rect: quadtree.Rect{X: -40, Y: 12, W: 8, H: 8}, Id: "id3"},
+		{Rect: quadtree.Rect{X: 100, Y: 100, W: 8, H: 8}, Id: "id4"},
+	}
+
+	type TestInput struct {
+		hitbox quadtree.Rect
+	}
+
+	const NAME string = "should find elements intersecting %+v"
+	getName := func(input TestInput) string {
+		return fmt.Sprintf(NAME, input.hitbox)
+	}
+
+	cases := []util.TestCase[TestInput, []quadtree.QuadElement]{
+		{
+			Name:     getName,
+			Input:    TestInput{quadtree.Rect{X: 0, Y: 0, W: 40, H: 40}},
+			Expected: []quadtree.QuadElement{els[0], els[1]},
+		},
+		{
+			Name:     getName,
+			Input:    TestInput{quadtree.Rect{X: -50, Y: 0, W: 20, H: 20}},
+			Expected: []quadtree.QuadElement{els[2]},
+		},
+		{
+			Name:     getName,
+			Input:    TestInput{quadtree.Rect{X: 50, Y: 50, W: 5, H: 5}},
+			Expected: []quadtree.QuadElement{},
+		},
+	}
+
+	util.IterateTestCases(cases, t, func(testCase util.TestCase[TestInput, []quadtree.QuadElement]) {
+		hash := NewSpatialHash(10)
+		for _, el := range els {
+			hash.Insert(el)
+		}
+
+		require.ElementsMatch(t, testCase.Expected, hash.Query(testCase.Input.hitbox))
+	})
+}
+
+func TestRemoveShouldDeleteElFromEveryCell(t *testing.T) {
+	hash := NewSpatialHash(10)
+	el1 := quadtree.QuadElement{Rect: quadtree.Rect{X: 5, Y: 5, W: 20, H: 20}, Id: "id1"}
+	el2 := quadtree.QuadElement{Rect: quadtree.Rect{X: 0, Y: 0, W: 5, H: 5}, Id: "id2"}
+
+	hash.Insert(el1)
+	hash.Insert(el2)
+	hash.Remove(el1)
+
+	require.Equal(t, map[cellKey][]quadtree.QuadElement{{0, 0}: {el2}}, hash.cells)
+	require.Equal(t, []quadtree.QuadElement{el2}, hash.Query(quadtree.Rect{X: 0, Y: 0, W: 30, H: 30}))
+
+	hash.Remove(el2)
+	require.Empty(t, hash.cells)
+}
+
+func TestNewSpatialHashPanicsWithInvalidCellSize(t *testing.T) {
+	defer func() {
+		r := recover()
+		require.NotNil(t, r)
+		require.Equal(t, "cell size must be positive", r)
+	}()
+
+	NewSpatialHash(0)
+}
+
+func TestMoveShouldUpdateCells(t *testing.T) {
+	type TestInput struct {
+		oldRect quadtree.Rect
+		newRect quadtree.Rect
+	}
+
+	const NAME string = "should move element from %+v to %+v"
+	getName := func(input TestInput) string {
+		return fmt.Sprintf(NAME, input.oldRect, input.newRect)
+	}
+
+	cases := []util.TestCase[TestInput, []cellKey]{
+		{
+			Name:     getName,
+			Input:    TestInput{quadtree.Rect{X: 1, Y: 1, W: 5, H: 5}, quadtree.Rect{X: 3, Y: 2, W: 5, H: 5}},
+			Expected: []cellKey{{0, 0}},
+		},
+		{
+			Name:     getName,
+			Input:    TestInput{quadtree.Rect{X: 1, Y: 1, W: 5, H: 5}, quadtree.Rect{X: 8, Y: 2, W: 5, H: 5}},
+			Expected: []cellKey{{0, 0}, {1, 0}},
+		},
+		{
+			Name:     getName,
+			Input:    TestInput{quadtree.Rect{X: 8, Y: 2, W: 5, H: 5}, quadtree.Rect{X: -8, Y: 2, W: 5, H: 5}},
+			Expected: []cellKey{{-1, 0}},
+		},
+	}
+
+	util.IterateTestCases(cases, t, func(testCase util.TestCase[TestInput, []cellKey]) {
+		hash := NewSpatialHash(10)
+		hash.Insert(quadtree.QuadElement{Rect: testCase.Input.oldRect, Id: "id1"})
+
+		hash.Move("id1", testCase.Input.oldRect, testCase.Input.newRect)
+
+		keys := make([]cellKey, 0)
+		for key, cell := range hash.cells {
+			require.Equal(t, []quadtree.QuadElement{{Rect: testCase.Input.newRect, Id: "id1"}}, cell)
+			keys = append(keys, key)
+		}
+
+		require.ElementsMatch(t, testCase.Expected, keys)
+	})
+}
+
+func TestVisitQueryShouldFilterAndStopEarly(t *testing.T) {
+	hash := NewSpatialHash(10)
+	for i := 0; i < 10; i++ {
+		// wide enough to span several cells and overlap its neighbours
+		hash.Insert(quadtree.QuadElement{Rect: quadtree.Rect{X: float32(i) * 10, Y: 0, W: 25, H: 25}, Id: fmt.Sprintf("id%d", i)})
+	}
+	onlyEven := func(el quadtree.QuadElement) bool {
+		return int(el.X/10)%2 == 0
+	}
+
+	visited := make([]string, 0)
+	hash.VisitQuery(quadtree.Rect{X: 0, Y: 0, W: 200, H: 200}, onlyEven, func(el quadtree.QuadElement) bool {
+		visited = append(visited, el.Id)
+		return true
+	})
+	require.ElementsMatch(t, []string{"id0", "id2", "id4", "id6", "id8"}, visited)
+
+	visitedCount := 0
+	hash.VisitQuery(quadtree.Rect{X: 0, Y: 0, W: 200, H: 200}, nil, func(el quadtree.QuadElement) bool {
+		visitedCount++
+		return visitedCount < 4
+	})
+	require.Equal(t, 4, visitedCount)
+
+	buf := make([]quadtree.QuadElement, 0, 10)
+	allocs := testing.AllocsPerRun(10, func() {
+		buf = hash.QueryAppend(quadtree.Rect{X: 0, Y: 0, W: 200, H: 200}, onlyEven, buf[:0])
+	})
+	require.Zero(t, allocs)
+	require.Len(t, buf, 5)
+}
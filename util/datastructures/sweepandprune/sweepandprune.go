@@ -0,0 +1,107 @@
+package sweepandprune
+
+import (
+	"slices"
+	"sort"
+
+	"github.com/TheRaizer/GolangGame/util/datastructures/quadtree"
+)
+
+// Keeps every element sorted along the x axis so that a query only sweeps over
+// the elements whose horizontal extent can overlap the hitbox, pruning everything else.
+type SweepAndPrune struct {
+	els  []quadtree.QuadElement // sorted by the left edge of their rects
+	maxW float32                // width of the widest inserted rect, bounds how far left a query must sweep
+}
+
+func NewSweepAndPrune() SweepAndPrune {
+	return SweepAndPrune{
+		els: make([]quadtree.QuadElement, 0),
+	}
+}
+
+// Inserts an element while keeping the elements sorted
+func (sap *SweepAndPrune) Insert(el quadtree.QuadElement) {
+	idx := sort.Search(len(sap.els), func(i int) bool {
+		return sap.els[i].X > el.X
+	})
+
+	sap.els = slices.Insert(sap.els, idx, el)
+	sap.maxW = max(sap.maxW, el.W)
+}
+
+// Queries for elements that lie inside the given rect
+func (sap *SweepAndPrune) Query(hitbox quadtree.Rect) []quadtree.QuadElement {
+	return sap.QueryAppend(hitbox, nil, nil)
+}
+
+// Appends the elements that lie inside the given rect and pass the filter to buf
+func (sap *SweepAndPrune) QueryAppend(hitbox quadtree.Rect, filter quadtree.Filter, buf []quadtree.QuadElement) []quadtree.QuadElement {
+	sap.VisitQuery(hitbox, filter, func(el quadtree.QuadElement) bool {
+		buf = append(buf, el)
+		return true
+	})
+
+	return buf
+}
+
+// Calls the visitor with each element that lies inside the given rect and passes the filter,
+// until the visitor returns false
+func (sap *SweepAndPrune) VisitQuery(hitbox quadtree.Rect, filter quadtree.Filter, visitor quadtree.Visitor) {
+	// no element starting at or before this point is wide enough to reach the hitbox
+	sweepStart := hitbox.X - sap.maxW
+	start := sort.Search(len(sap.els), func(i int) bool {
+		return sap.els[i].X > sweepStart
+	})
+
+	for i := start; i < len(sap.els) && sap.els[i].X < hitbox.Right(); i++ {
+		el := sap.els[i]
+		if hitbox.Intersects(el.Rect) && (filter == nil || filter(el)) && !visitor(el) {
+			return
+		}
+	}
+}
+
+// Removes an element from the sorted elements
+func (sap *SweepAndPrune) Remove(el quadtree.QuadElement) {
+	if i := sap.indexOf(el.Id, el.Rect); i != -1 {
+		sap.els = slices.Delete(sap.els, i, i+1)
+	}
+}
+
+// Moves an element from oldRect to newRect.
+// Elements move little between frames, so rather than reinserting, the element is
+// shifted past its neighbours until the elements are sorted again.
+func (sap *SweepAndPrune) Move(id string, oldRect quadtree.Rect, newRect quadtree.Rect) {
+	i := sap.indexOf(id, oldRect)
+	if i == -1 {
+		sap.Insert(quadtree.QuadElement{Rect: newRect, Id: id})
+		return
+	}
+
+	sap.els[i].Rect = newRect
+	sap.maxW = max(sap.maxW, newRect.W)
+
+	for ; i > 0 && sap.els[i-1].X > newRect.X; i-- {
+		sap.els[i-1], sap.els[i] = sap.els[i], sap.els[i-1]
+	}
+
+	for ; i < len(sap.els)-1 && sap.els[i+1].X < newRect.X; i++ {
+		sap.els[i+1], sap.els[i] = sap.els[i], sap.els[i+1]
+	}
+}
+
+// returns the index of the element with the given id and rect, or -1 if there is none
+func (sap *SweepAndPrune) indexOf(id string, rect quadtree.Rect) int {
+	start := sort.Search(len(sap.els), func(i int) bool {
+		return sap.els[i].X >= rect.X
+	})
+
+	for i := start; i < len(sap.els) && sap.els[i].X == rect.X; i++ {
+		if sap.els[i].Id == id {
+			return i
+		}
+	}
+
+	return -1
+}
@@ -0,0 +1,198 @@
+package sweepandprune
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/TheRaizer/GolangGame/util"
+	"github.com/TheRaizer/GolangGame/util/datastructures/quadtree"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInsertShouldKeepElsSorted(t *testing.T) {
+	const NAME string = "should keep elements %+v sorted by their left edge"
+	getName := func(input []quadtree.QuadElement) string {
+		return fmt.Sprintf(NAME, input)
+	}
+
+	cases := []util.TestCase[[]quadtree.QuadElement, []string]{
+		{
+			Name: getName,
+			Input: []quadtree.QuadElement{
+				{Rect: quadtree.Rect{X: 30, Y: 0, W: 5, H: 5}, Id: "id1"},
+				{Rect: quadtree.Rect{X: -4, Y: 8, W: 5, H: 5}, Id: "id2"},
+				{Rect: quadtree.Rect{X: 12, Y: 3, W: 50, H: 5}, Id: "id3"},
+			},
+			Expected: []string{"id2", "id3", "id1"},
+		},
+		{
+			Name: getName,
+			Input: []quadtree.QuadElement{
+				{Rect: quadtree.Rect{X: 5, Y: 0, W: 5, H: 5}, Id: "id1"},
+				{Rect: quadtree.Rect{X: 5, Y: 8, W: 5, H: 5}, Id: "id2"},
+				{Rect: quadtree.Rect{X: 0, Y: 3, W: 5, H: 5}, Id: "id3"},
+			},
+			Expected: []string{"id3", "id1", "id2"},
+		},
+	}
+
+	util.IterateTestCases(cases, t, func(testCase util.TestCase[[]quadtree.QuadElement, []string]) {
+		sap := NewSweepAndPrune()
+		for _, el := range testCase.Input {
+			sap.Insert(el)
+		}
+
+		ids := make([]string, 0)
+		for _, el := range sap.els {
+			ids = append(ids, el.Id)
+		}
+
+		require.Equal(t, testCase.Expected, ids)
+	})
+}
+
+func TestQueryShouldFindIntersectingEls(t *testing.T) {
+	els := []quadtree.QuadElement{
+		{Rect: quadtree.Rect{X: 0, Y: 0, W: 5, H: 5}, Id: "id1"},
+		{Rect: quadtree.Rect{X: -100, Y: 10, W: 300, H: 10}, Id: "id2"},
+		{Rect: quadtree.Rect{X: 40, Y: 40, W: 8, H: 8}, Id: "id3"},
+		{Rect: quadtree.Rect{X: 44, Y: 0, W: 8, H: 8}, Id: "id4"},
+	}
+
+	const NAME string = "should find elements intersecting %+v"
+	getName := func(input quadtree.Rect) string {
+		return fmt.Sprintf(NAME, input)
+	}
+
+	cases := []util.TestCase[quadtree.Rect, []quadtree.QuadElement]{
+		{
+			Name:     getName,
+			Input:    quadtree.Rect{X: 0, Y: 0, W: 20, H: 20},
+			Expected: []quadtree.QuadElement{els[0], els[1]},
+		},
+		{
+			Name:     getName,
+			Input:    quadtree.Rect{X: 150, Y: 15, W: 5, H: 5},
+			Expected: []quadtree.QuadElement{els[1]},
+		},
+		{
+			Name:     getName,
+			Input:    quadtree.Rect{X: 42, Y: 0, W: 5, H: 45},
+			Expected: []quadtree.QuadElement{els[1], els[2], els[3]},
+		},
+		{
+			Name:     getName,
+			Input:    quadtree.Rect{X: 300, Y: 0, W: 5, H: 5},
+			Expected: []quadtree.QuadElement{},
+		},
+	}
+
+	util.IterateTestCases(cases, t, func(testCase util.TestCase[quadtree.Rect, []quadtree.QuadElement]) {
+		sap := NewSweepAndPrune()
+		for _, el := range els {
+			sap.Insert(el)
+		}
+
+		require.ElementsMatch(t, testCase.Expected, sap.Query(testCase.Input))
+	})
+}
+
+func TestRemoveShouldOnlyRemoveMatchingId(t *testing.T) {
+	sap := NewSweepAndPrune()
+	el1 := quadtree.QuadElement{Rect: quadtree.Rect{X: 5, Y: 0, W: 5, H: 5}, Id: "id1"}
+	el2 := quadtree.QuadElement{Rect: quadtree.Rect{X: 5, Y: 0, W: 5, H: 5}, Id: "id2"}
+
+	sap.Insert(el1)
+	sap.Insert(el2)
+	sap.Remove(el2)
+
+	require.Equal(t, []quadtree.QuadElement{el1}, sap.els)
+
+	// removing an element that was never inserted does nothing
+	sap.Remove(quadtree.QuadElement{Rect: quadtree.Rect{X: 1, Y: 1, W: 1, H: 1}, Id: "id3"})
+	require.Equal(t, []quadtree.QuadElement{el1}, sap.els)
+}
+
+func TestMoveShouldKeepElsSorted(t *testing.T) {
+	type TestInput struct {
+		id      string
+		newRect quadtree.Rect
+	}
+
+	const NAME string = "should keep elements sorted after moving %s to %+v"
+	getName := func(input TestInput) string {
+		return fmt.Sprintf(NAME, input.id, input.newRect)
+	}
+
+	cases := []util.TestCase[TestInput, []string]{
+		{
+			Name:     getName,
+			Input:    TestInput{"id2", quadtree.Rect{X: 12, Y: 0, W: 5, H: 5}},
+			Expected: []string{"id1", "id2", "id3"},
+		},
+		{
+			Name:     getName,
+			Input:    TestInput{"id1", quadtree.Rect{X: 25, Y: 0, W: 5, H: 5}},
+			Expected: []string{"id2", "id3", "id1"},
+		},
+		{
+			Name:     getName,
+			Input:    TestInput{"id3", quadtree.Rect{X: -5, Y: 0, W: 5, H: 5}},
+			Expected: []string{"id3", "id1", "id2"},
+		},
+	}
+
+	util.IterateTestCases(cases, t, func(testCase util.TestCase[TestInput, []string]) {
+		sap := NewSweepAndPrune()
+		rects := map[string]quadtree.Rect{
+			"id1": {X: 0, Y: 0, W: 5, H: 5},
+			"id2": {X: 10, Y: 0, W: 5, H: 5},
+			"id3": {X: 20, Y: 0, W: 5, H: 5},
+		}
+		for id, rect := range rects {
+			sap.Insert(quadtree.QuadElement{Rect: rect, Id: id})
+		}
+
+		sap.Move(testCase.Input.id, rects[testCase.Input.id], testCase.Input.newRect)
+
+		ids := make([]string, 0)
+		for _, el := range sap.els {
+			ids = append(ids, el.Id)
+		}
+
+		require.Equal(t, testCase.Expected, ids)
+		require.Equal(t, []quadtree.QuadElement{{Rect: testCase.Input.newRect, Id: testCase.Input.id}}, sap.Query(testCase.Input.newRect))
+	})
+}
+
+func TestVisitQueryShouldFilterAndStopEarly(t *testing.T) {
+	sap := NewSweepAndPrune()
+	for i := 0; i < 10; i++ {
+		// wide enough to span several cells and overlap its neighbours
+		sap.Insert(quadtree.QuadElement{Rect: quadtree.Rect{X: float32(i) * 10, Y: 0, W: 25, H: 25}, Id: fmt.Sprintf("id%d", i)})
+	}
+	onlyEven := func(el quadtree.QuadElement) bool {
+		return int(el.X/10)%2 == 0
+	}
+
+	visited := make([]string, 0)
+	sap.VisitQuery(quadtree.Rect{X: 0, Y: 0, W: 200, H: 200}, onlyEven, func(el quadtree.QuadElement) bool {
+		visited = append(visited, el.Id)
+		return true
+	})
+	require.ElementsMatch(t, []string{"id0", "id2", "id4", "id6", "id8"}, visited)
+
+	visitedCount := 0
+	sap.VisitQuery(quadtree.Rect{X: 0, Y: 0, W: 200, H: 200}, nil, func(el quadtree.QuadElement) bool {
+		visitedCount++
+		return visitedCount < 4
+	})
+	require.Equal(t, 4, visitedCount)
+
+	buf := make([]quadtree.QuadElement, 0, 10)
+	allocs := testing.AllocsPerRun(10, func() {
+		buf = sap.QueryAppend(quadtree.Rect{X: 0, Y: 0, W: 200, H: 200}, onlyEven, buf[:0])
+	})
+	require.Zero(t, allocs)
+	require.Len(t, buf, 5)
+}
@@ -1,26 +1,57 @@
 package util
 
+import "math"
+
+// Vectors are values, so the methods return a new vector rather than changing the one they are called on
 type Vec2[T int8 | int16 | int32 | int64 | float32 | float64] struct {
 	X, Y T
 }
 
-func (vec *Vec2[T]) Add(otherVec *Vec2[T]) *Vec2[T] {
-	vec.X += otherVec.X
-	vec.Y += otherVec.Y
+func (vec Vec2[T]) Add(otherVec Vec2[T]) Vec2[T] {
+	return Vec2[T]{X: vec.X + otherVec.X, Y: vec.Y + otherVec.Y}
+}
+
+func (vec Vec2[T]) Sub(otherVec Vec2[T]) Vec2[T] {
+	return Vec2[T]{X: vec.X - otherVec.X, Y: vec.Y - otherVec.Y}
+}
 
-	return vec
+func (vec Vec2[T]) Multiply(num T) Vec2[T] {
+	return Vec2[T]{X: vec.X * num, Y: vec.Y * num}
 }
 
-func (vec *Vec2[T]) Multiply(num T) *Vec2[T] {
-	vec.X *= num
-	vec.Y *= num
+func (vec Vec2[T]) Divide(num T) Vec2[T] {
+	return Vec2[T]{X: vec.X / num, Y: vec.Y / num}
+}
+
+func (vec Vec2[T]) Dot(otherVec Vec2[T]) T {
+	return vec.X*otherVec.X + vec.Y*otherVec.Y
+}
 
-	return vec
+// The z component of the cross product of the vectors
+func (vec Vec2[T]) Cross(otherVec Vec2[T]) T {
+	return vec.X*otherVec.Y - vec.Y*otherVec.X
 }
 
-func (vec *Vec2[T]) Divide(num T) *Vec2[T] {
-	vec.X /= num
-	vec.Y /= num
+func (vec Vec2[T]) LengthSquared() T {
+	return vec.Dot(vec)
+}
+
+// Rounded towards zero for integer vectors
+func (vec Vec2[T]) Length() T {
+	return T(math.Sqrt(float64(vec.LengthSquared())))
+}
+
+// Returns the vector scaled to a length of 1, or the zero vector when the vector has no length.
+// Computed in float64, so the components of an integer vector are rounded towards zero.
+func (vec Vec2[T]) Normalize() Vec2[T] {
+	length := math.Sqrt(float64(vec.LengthSquared()))
+	if length == 0 {
+		return Vec2[T]{}
+	}
+
+	return Vec2[T]{X: T(float64(vec.X) / length), Y: T(float64(vec.Y) / length)}
+}
 
-	return vec
+func (vec Vec2[T]) Midpoint(otherVec Vec2[T]) Vec2[T] {
+	return Vec2[T]{X: (vec.X + otherVec.X) / 2, Y: (vec.Y + otherVec.Y) / 2}
 }
@@ -0,0 +1,36 @@
+package util
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalize(t *testing.T) {
+	const NAME string = "should normalize %+v"
+	getName := func(input Vec2[float32]) string {
+		return fmt.Sprintf(NAME, input)
+	}
+
+	cases := []TestCase[Vec2[float32], Vec2[float32]]{
+		{Name: getName, Input: Vec2[float32]{X: 3, Y: -4}, Expected: Vec2[float32]{X: 0.6, Y: -0.8}},
+		{Name: getName, Input: Vec2[float32]{X: 0, Y: 0.5}, Expected: Vec2[float32]{X: 0, Y: 1}},
+		// a vector without a length has no direction to keep
+		{Name: getName, Input: Vec2[float32]{}, Expected: Vec2[float32]{}},
+	}
+
+	IterateTestCases(cases, t, func(testCase TestCase[Vec2[float32], Vec2[float32]]) {
+		normalized := testCase.Input.Normalize()
+
+		require.InDelta(t, testCase.Expected.X, normalized.X, 1e-6)
+		require.InDelta(t, testCase.Expected.Y, normalized.Y, 1e-6)
+	})
+}
+
+func TestNormalizeShouldRoundIntegerVectors(t *testing.T) {
+	require.Equal(t, Vec2[int32]{X: 0, Y: -1}, Vec2[int32]{X: 0, Y: -7}.Normalize())
+	require.Equal(t, Vec2[int32]{X: 0, Y: 0}, Vec2[int32]{X: 3, Y: 4}.Normalize())
+	require.Equal(t, Vec2[int32]{}, Vec2[int32]{}.Normalize())
+	require.Equal(t, int32(5), Vec2[int32]{X: 3, Y: 4}.Length())
+}